@@ -0,0 +1,140 @@
+package jwt
+
+import (
+	"fmt"
+	"time"
+)
+
+// TypeAccessToken is typ header parameter of json web token access tokens.
+// https://datatracker.ietf.org/doc/html/rfc9068#section-2.1
+const TypeAccessToken = "at+jwt"
+
+// ClaimClientID is client_id claim.
+// https://datatracker.ietf.org/doc/html/rfc8693#section-4.3
+const ClaimClientID = "client_id"
+
+// accessTokenClaims are required claims of access tokens.
+// https://datatracker.ietf.org/doc/html/rfc9068#section-2.2
+var accessTokenClaims = []string{
+	ClaimIssuer, ClaimExpirationTime, ClaimAudience, ClaimSubject, ClaimClientID, ClaimIssuedAt, ClaimJWTID,
+}
+
+// NewAccessToken returns new access token of typ at+jwt issued now with a random jwt id.
+func NewAccessToken(alg Algorithm) (*Token, error) {
+	jti, err := newJWTID()
+	if err != nil {
+		return nil, err
+	}
+
+	token := New(alg)
+	token.header.Type = TypeAccessToken
+	token.SetIssuedAt(time.Now())
+	token.SetJWTID(jti)
+
+	return token, nil
+}
+
+func (t *Token) SetClientID(clientID string) {
+	t.Set(ClaimClientID, clientID)
+}
+
+func (t Token) GetClientID() (string, error) {
+	value, exists := t.payload[ClaimClientID]
+	if !exists {
+		return "", ErrClaimNotFound
+	}
+
+	clientID, ok := value.(string)
+	if !ok {
+		return "", ErrInvalidClaimType
+	}
+
+	return clientID, nil
+}
+
+// SignAccessToken signs the token as access token of typ at+jwt.
+// Tokens without any of the required claims are rejected with ErrClaimNotFound.
+func (s Signer) SignAccessToken(token Token) (string, error) {
+	err := requireClaims(token, accessTokenClaims...)
+	if err != nil {
+		return "", err
+	}
+
+	token.header.Type = TypeAccessToken
+
+	return s.Sign(token)
+}
+
+// AccessTokenValidator validates access tokens of an issuer for a resource server.
+// https://datatracker.ietf.org/doc/html/rfc9068#section-4
+type AccessTokenValidator struct {
+	issuer   string
+	audience string
+}
+
+// NewAccessTokenValidator returns access token validator of the issuer and the resource server audience.
+func NewAccessTokenValidator(issuer, audience string) *AccessTokenValidator {
+	return &AccessTokenValidator{
+		issuer:   issuer,
+		audience: audience,
+	}
+}
+
+// Verify access token string with the verifier, validate its claims and return it.
+// Tokens of other types than at+jwt, such as ID tokens, are rejected with ErrUnsupportedTokenType.
+func (v AccessTokenValidator) Verify(t string, verifier TokenVerifier) (*Token, error) {
+	token, err := verifier.VerifyType(t, TypeAccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	err = v.Validate(*token)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// Validate the claims of verified access token.
+func (v AccessTokenValidator) Validate(token Token) error {
+	if !isType(token.header.Type, TypeAccessToken) {
+		return ErrUnsupportedTokenType
+	}
+
+	err := requireClaims(token, accessTokenClaims...)
+	if err != nil {
+		return err
+	}
+
+	if iss, err := token.GetIssuer(); err != nil || iss != v.issuer {
+		return ErrInvalidIssuer
+	}
+
+	if aud, err := token.GetAudience(); err != nil || !containsAll(aud, []string{v.audience}) {
+		return ErrInvalidAudience
+	}
+
+	return token.Validate()
+}
+
+func requireClaims(token Token, claims ...string) error {
+	for i := range claims {
+		if _, ok := token.payload[claims[i]]; !ok {
+			return fmt.Errorf("%w: %s", ErrClaimNotFound, claims[i])
+		}
+	}
+
+	return nil
+}
+
+const jwtIDSize = 16
+
+func newJWTID() (string, error) {
+	jti, err := generateSecret(jwtIDSize)
+	if err != nil {
+		return "", fmt.Errorf("error on generate jwt id: %w", err)
+	}
+
+	return encodeBytes(jti), nil
+}
@@ -0,0 +1,152 @@
+package jwt_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestAccessToken(t *testing.T) {
+	t.Parallel()
+
+	const (
+		issuer   = "https://as.example.com"
+		audience = "https://rs.example.com"
+	)
+
+	signer, err := jwt.NewSigner(jwt.RS256, []byte(private))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := jwt.NewVerifier(jwt.RS256, []byte(public))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validator := jwt.NewAccessTokenValidator(issuer, audience)
+
+	accessToken := func(t *testing.T) *jwt.Token {
+		t.Helper()
+
+		token, err := jwt.NewAccessToken(jwt.RS256)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		token.SetIssuer(issuer)
+		token.SetSubject("5ba552d67")
+		token.SetAudience(audience)
+		token.SetClientID("s6BhdRkqt3")
+		token.SetExpirationTime(time.Now().Add(time.Hour))
+
+		return token
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+
+		tokenStr, err := signer.SignAccessToken(*accessToken(t))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		token, err := validator.Verify(tokenStr, *verifier)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if typ := token.GetHeader().Type; typ != jwt.TypeAccessToken {
+			t.Errorf("excepted: %q, got: %q", jwt.TypeAccessToken, typ)
+		}
+
+		clientID, err := token.GetClientID()
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if clientID != "s6BhdRkqt3" {
+			t.Errorf("excepted: %q, got: %q", "s6BhdRkqt3", clientID)
+		}
+
+		if _, err := token.GetJWTID(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Missing Required Claim", func(t *testing.T) {
+		t.Parallel()
+
+		token := accessToken(t)
+		delete(token.GetPayload(), jwt.ClaimClientID)
+
+		_, err := signer.SignAccessToken(*token)
+		if !errors.Is(err, jwt.ErrClaimNotFound) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrClaimNotFound, err)
+		}
+	})
+
+	t.Run("Reject ID Token", func(t *testing.T) {
+		t.Parallel()
+
+		token := accessToken(t)
+
+		idToken := jwt.New(jwt.RS256)
+		for k, v := range token.GetPayload() {
+			idToken.Set(k, v)
+		}
+
+		tokenStr, err := signer.Sign(*idToken)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_, err = validator.Verify(tokenStr, *verifier)
+		if !errors.Is(err, jwt.ErrUnsupportedTokenType) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrUnsupportedTokenType, err)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		modify func(token *jwt.Token)
+		err    error
+	}{
+		{"Wrong Issuer", func(token *jwt.Token) { token.SetIssuer("https://evil.example.com") }, jwt.ErrInvalidIssuer},
+		{"Wrong Audience", func(token *jwt.Token) { token.SetAudience("https://other.example.com") }, jwt.ErrInvalidAudience},
+		{"Expired", func(token *jwt.Token) { token.SetExpirationTime(time.Now().Add(-time.Minute)) }, jwt.ErrTokenExpired},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			token := accessToken(t)
+			tt.modify(token)
+
+			tokenStr, err := signer.SignAccessToken(*token)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			_, err = validator.Verify(tokenStr, *verifier)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("excepted error %q, got %q", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GrantTypeJWTBearer is grant_type parameter of jwt authorization grants.
+// https://datatracker.ietf.org/doc/html/rfc7523#section-2.1
+const GrantTypeJWTBearer = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+
+// Authorization Grant Parameters.
+const (
+	ParameterGrantType = "grant_type"
+	ParameterAssertion = "assertion"
+	ParameterScope     = "scope"
+)
+
+// DefaultAuthorizationGrantLifetime is the lifetime of signed authorization grants without exp claim.
+const DefaultAuthorizationGrantLifetime = 5 * time.Minute
+
+var ErrUnsupportedGrantType = errors.New("unsupported grant type")
+
+var authorizationGrantClaims = []string{ClaimIssuer, ClaimSubject, ClaimAudience, ClaimExpirationTime}
+
+// SignAuthorizationGrant signs the token as jwt authorization grant.
+// The token must have iss, sub and aud claims. Missing iat and jti claims are set,
+// and exp is set to DefaultAuthorizationGrantLifetime when missing.
+// https://datatracker.ietf.org/doc/html/rfc7523#section-3
+func (s Signer) SignAuthorizationGrant(token Token) (string, error) {
+	now := time.Now()
+
+	grant := token.clone()
+
+	if _, err := grant.GetIssuedAt(); err != nil {
+		grant.SetIssuedAt(now)
+	}
+
+	if _, err := grant.GetJWTID(); err != nil {
+		jti, err := newJWTID()
+		if err != nil {
+			return "", err
+		}
+
+		grant.SetJWTID(jti)
+	}
+
+	if _, err := grant.GetExpirationTime(); err != nil {
+		grant.SetExpirationTime(now.Add(DefaultAuthorizationGrantLifetime))
+	}
+
+	err := requireClaims(grant, authorizationGrantClaims...)
+	if err != nil {
+		return "", err
+	}
+
+	return s.Sign(grant)
+}
+
+// AuthorizationGrantParameters returns the token request parameters of the authorization grant.
+func AuthorizationGrantParameters(assertion string, scopes ...string) url.Values {
+	params := url.Values{
+		ParameterGrantType: {GrantTypeJWTBearer},
+		ParameterAssertion: {assertion},
+	}
+
+	if len(scopes) > 0 {
+		params.Set(ParameterScope, strings.Join(scopes, " "))
+	}
+
+	return params
+}
+
+// AuthorizationGrantFromParameters returns the authorization grant of token request parameters.
+func AuthorizationGrantFromParameters(params url.Values) (string, error) {
+	if params.Get(ParameterGrantType) != GrantTypeJWTBearer {
+		return "", ErrUnsupportedGrantType
+	}
+
+	assertion := params.Get(ParameterAssertion)
+	if assertion == "" {
+		return "", ErrTokenNotFound
+	}
+
+	return assertion, nil
+}
+
+// AuthorizationGrant is validated jwt authorization grant.
+type AuthorizationGrant struct {
+	// Subject is the local subject the grant is issued for, as mapped by AuthorizationGrantWithSubjectMapper.
+	Subject string
+	Token   *Token
+}
+
+// AccessToken signs access token of the grant subject issued by the issuer to the client
+// for the resource server audience. It expires after the lifetime and has the scopes of the token request, if any.
+// https://datatracker.ietf.org/doc/html/rfc7523#section-2.1
+func (g AuthorizationGrant) AccessToken(
+	signer Signer, issuer, audience, clientID string, lifetime time.Duration, scopes ...string,
+) (string, error) {
+	token, err := NewAccessToken(signer.alg)
+	if err != nil {
+		return "", err
+	}
+
+	token.SetIssuer(issuer)
+	token.SetSubject(g.Subject)
+	token.SetAudience(audience)
+	token.SetClientID(clientID)
+	token.SetExpirationTime(time.Now().Add(lifetime))
+
+	if len(scopes) > 0 {
+		token.SetScope(scopes...)
+	}
+
+	return signer.SignAccessToken(*token)
+}
+
+// AuthorizationGrantValidator validates jwt authorization grants of trusted issuers.
+// https://datatracker.ietf.org/doc/html/rfc7523#section-3
+type AuthorizationGrantValidator struct {
+	audiences     []string
+	issuers       map[string]TokenVerifier
+	subjectMapper func(token Token) (string, error)
+}
+
+// AuthorizationGrantOption configures authorization grant validator.
+type AuthorizationGrantOption func(*AuthorizationGrantValidator)
+
+// AuthorizationGrantWithIssuer trusts grants of the issuer verified by the verifier, e.g. KeySetVerifier of its keys.
+func AuthorizationGrantWithIssuer(issuer string, verifier TokenVerifier) AuthorizationGrantOption {
+	return func(v *AuthorizationGrantValidator) {
+		v.issuers[issuer] = verifier
+	}
+}
+
+// AuthorizationGrantWithSubjectMapper sets the mapping of grants to local subjects, e.g. accounts linked to partner users.
+// Grants are mapped to their sub claim by default.
+func AuthorizationGrantWithSubjectMapper(mapper func(token Token) (string, error)) AuthorizationGrantOption {
+	return func(v *AuthorizationGrantValidator) {
+		v.subjectMapper = mapper
+	}
+}
+
+// NewAuthorizationGrantValidator returns authorization grant validator that accepts grants
+// for any of the audiences, e.g. the token endpoint url and the issuer identifier of the authorization server.
+func NewAuthorizationGrantValidator(audiences []string, opts ...AuthorizationGrantOption) *AuthorizationGrantValidator {
+	v := AuthorizationGrantValidator{
+		audiences: audiences,
+		issuers:   make(map[string]TokenVerifier),
+		subjectMapper: func(token Token) (string, error) {
+			return token.GetSubject()
+		},
+	}
+
+	for i := range opts {
+		opts[i](&v)
+	}
+
+	return &v
+}
+
+// Verify authorization grant string with the verifier of its issuer, validate its claims and map its subject.
+// Grants of untrusted issuers are rejected with ErrInvalidIssuer.
+func (v AuthorizationGrantValidator) Verify(t string) (*AuthorizationGrant, error) {
+	unverified, err := Parse(t)
+	if err != nil {
+		return nil, err
+	}
+
+	iss, err := unverified.GetIssuer()
+	if err != nil {
+		return nil, ErrInvalidIssuer
+	}
+
+	verifier, ok := v.issuers[iss]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not trusted", ErrInvalidIssuer, iss)
+	}
+
+	token, err := verifier.VerifyType(t, "", typeJWT)
+	if err != nil {
+		return nil, err
+	}
+
+	err = v.Validate(*token)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := v.subjectMapper(*token)
+	if err != nil {
+		return nil, fmt.Errorf("error on map subject: %w", err)
+	}
+
+	return &AuthorizationGrant{Subject: sub, Token: token}, nil
+}
+
+// Validate the claims of verified authorization grant.
+func (v AuthorizationGrantValidator) Validate(token Token) error {
+	err := requireClaims(token, authorizationGrantClaims...)
+	if err != nil {
+		return err
+	}
+
+	iss, err := token.GetIssuer()
+	if err != nil {
+		return ErrInvalidIssuer
+	}
+
+	if _, ok := v.issuers[iss]; !ok {
+		return fmt.Errorf("%w: %s is not trusted", ErrInvalidIssuer, iss)
+	}
+
+	if aud, err := token.GetAudience(); err != nil || !containsAny(aud, v.audiences) {
+		return ErrInvalidAudience
+	}
+
+	return token.Validate()
+}
@@ -0,0 +1,240 @@
+package jwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestAuthorizationGrant(t *testing.T) {
+	t.Parallel()
+
+	const (
+		partner       = "https://idp.partner.example"
+		tokenEndpoint = "https://as.example.com/token"
+	)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwk, err := jwt.NewJWK(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwk.KeyID = "partner-1"
+	jwk.Algorithm = string(jwt.ES256)
+
+	signer, err := jwt.NewJWKSigner(*jwk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errUnknownUser := errors.New("unknown user")
+
+	keySet := jwt.JWKSet{Keys: []jwt.JWK{*jwk}}
+
+	validator := jwt.NewAuthorizationGrantValidator(
+		[]string{tokenEndpoint, "https://as.example.com"},
+		jwt.AuthorizationGrantWithIssuer(partner, jwt.NewKeySetVerifier(keySet.Public(), []jwt.Algorithm{jwt.ES256})),
+		jwt.AuthorizationGrantWithSubjectMapper(func(token jwt.Token) (string, error) {
+			sub, err := token.GetSubject()
+			if err != nil {
+				return "", err
+			}
+
+			if sub != "partner-user" {
+				return "", errUnknownUser
+			}
+
+			return "local-user", nil
+		}),
+	)
+
+	grant := func(modify func(token *jwt.Token)) *jwt.Token {
+		token := jwt.New(jwt.ES256)
+		token.SetIssuer(partner)
+		token.SetSubject("partner-user")
+		token.SetAudience("https://as.example.com")
+		modify(token)
+
+		return token
+	}
+
+	t.Run("Round Trip", func(t *testing.T) {
+		t.Parallel()
+
+		assertion, err := signer.SignAuthorizationGrant(*grant(func(token *jwt.Token) {}))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		received, err := jwt.AuthorizationGrantFromParameters(jwt.AuthorizationGrantParameters(assertion, "read"))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		res, err := validator.Verify(received)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if res.Subject != "local-user" {
+			t.Errorf("excepted: %q, got: %q", "local-user", res.Subject)
+		}
+
+		if _, err := res.Token.GetJWTID(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Keep Timestamps", func(t *testing.T) {
+		t.Parallel()
+
+		issuedAt := time.Now().Add(-time.Minute).Truncate(time.Second)
+
+		assertion, err := signer.SignAuthorizationGrant(*grant(func(token *jwt.Token) {
+			token.SetIssuedAt(issuedAt)
+			token.SetJWTID("grant-1")
+		}))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		res, err := validator.Verify(assertion)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if iat, _ := res.Token.GetIssuedAt(); !iat.Equal(issuedAt) {
+			t.Errorf("excepted: %s, got: %s", issuedAt, iat)
+		}
+
+		if jti, _ := res.Token.GetJWTID(); jti != "grant-1" {
+			t.Errorf("excepted: %q, got: %q", "grant-1", jti)
+		}
+	})
+
+	t.Run("Access Token", func(t *testing.T) {
+		t.Parallel()
+
+		assertion, err := signer.SignAuthorizationGrant(*grant(func(token *jwt.Token) {}))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		res, err := validator.Verify(assertion)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		accessToken, err := res.AccessToken(*signer, "https://as.example.com", "https://rs.example.com", "client", time.Hour, "read")
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		verifier := jwt.NewKeySetVerifier(keySet.Public(), []jwt.Algorithm{jwt.ES256})
+
+		token, err := jwt.NewAccessTokenValidator("https://as.example.com", "https://rs.example.com").Verify(accessToken, verifier)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if sub, _ := token.GetSubject(); sub != "local-user" {
+			t.Errorf("excepted: %q, got: %q", "local-user", sub)
+		}
+
+		if scopes, _ := token.GetScopes(); len(scopes) != 1 || scopes[0] != "read" {
+			t.Errorf("excepted: %q, got: %q", []string{"read"}, scopes)
+		}
+	})
+
+	t.Run("Grant Type", func(t *testing.T) {
+		t.Parallel()
+
+		params := jwt.AuthorizationGrantParameters("assertion")
+		params.Set(jwt.ParameterGrantType, "client_credentials")
+
+		_, err := jwt.AuthorizationGrantFromParameters(params)
+		if !errors.Is(err, jwt.ErrUnsupportedGrantType) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrUnsupportedGrantType, err)
+		}
+	})
+
+	t.Run("Missing Claims", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := signer.SignAuthorizationGrant(*jwt.New(jwt.ES256))
+		if !errors.Is(err, jwt.ErrClaimNotFound) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrClaimNotFound, err)
+		}
+	})
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherSigner, err := jwt.NewCryptoSigner(jwt.ES256, otherKey, jwt.SignWithKeyID("partner-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		signer *jwt.Signer
+		token  *jwt.Token
+		err    error
+	}{
+		{"Untrusted Issuer", signer, grant(func(token *jwt.Token) { token.SetIssuer("https://idp.other.example") }), jwt.ErrInvalidIssuer},
+		{"Other Key", otherSigner, grant(func(token *jwt.Token) {}), jwt.ErrInvalidTokenSignature},
+		{"Audience", signer, grant(func(token *jwt.Token) { token.SetAudience("https://rs.example.com") }), jwt.ErrInvalidAudience},
+		{"Expired", signer, grant(func(token *jwt.Token) { token.SetExpirationTime(time.Now().Add(-time.Minute)) }), jwt.ErrTokenExpired},
+		{"Unmapped Subject", signer, grant(func(token *jwt.Token) { token.SetSubject("unknown") }), errUnknownUser},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assertion, err := tt.signer.SignAuthorizationGrant(*tt.token)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			_, err = validator.Verify(assertion)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("excepted error %q, got %q", tt.err, err)
+			}
+		})
+	}
+}
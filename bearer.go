@@ -0,0 +1,207 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Bearer token error codes.
+// https://datatracker.ietf.org/doc/html/rfc6750#section-3.1
+const (
+	BearerErrorInvalidRequest    = "invalid_request"
+	BearerErrorInvalidToken      = "invalid_token"
+	BearerErrorInsufficientScope = "insufficient_scope"
+)
+
+const bearerScheme = "Bearer"
+
+type tokenContextKey struct{}
+
+// ContextWithToken returns a copy of the context with the token.
+func ContextWithToken(ctx context.Context, token *Token) context.Context {
+	return context.WithValue(ctx, tokenContextKey{}, token)
+}
+
+// TokenFromContext returns the token of the context put by BearerMiddleware.
+func TokenFromContext(ctx context.Context) (*Token, bool) {
+	token, ok := ctx.Value(tokenContextKey{}).(*Token)
+
+	return token, ok && token != nil
+}
+
+// BearerMiddleware authenticates requests by bearer tokens.
+// https://datatracker.ietf.org/doc/html/rfc6750
+type BearerMiddleware struct {
+	verifier   TokenVerifier
+	extractor  Extractor
+	realm      string
+	types      []string
+	validators []func(*Token) error
+}
+
+// BearerOption configures bearer middleware.
+type BearerOption func(*BearerMiddleware)
+
+// BearerWithRealm sets realm attribute of WWW-Authenticate response header.
+func BearerWithRealm(realm string) BearerOption {
+	return func(m *BearerMiddleware) {
+		m.realm = realm
+	}
+}
+
+// BearerWithExtractor sets the extractor of request tokens. It is HeaderExtractor by default.
+func BearerWithExtractor(extractor Extractor) BearerOption {
+	return func(m *BearerMiddleware) {
+		m.extractor = extractor
+	}
+}
+
+// BearerWithTypes sets the accepted typ header parameter values, e.g. at+jwt.
+// It accepts tokens of JWT type or without typ header parameter by default.
+func BearerWithTypes(types ...string) BearerOption {
+	return func(m *BearerMiddleware) {
+		m.types = types
+	}
+}
+
+// BearerWithValidator adds a validation of the verified token, e.g. of its issuer or audience.
+func BearerWithValidator(validate func(*Token) error) BearerOption {
+	return func(m *BearerMiddleware) {
+		m.validators = append(m.validators, validate)
+	}
+}
+
+// NewBearerMiddleware returns bearer middleware that verifies tokens with the verifier.
+func NewBearerMiddleware(verifier TokenVerifier, opts ...BearerOption) *BearerMiddleware {
+	m := BearerMiddleware{
+		verifier:  verifier,
+		extractor: HeaderExtractor(),
+		types:     []string{"", typeJWT},
+	}
+
+	for i := range opts {
+		opts[i](&m)
+	}
+
+	return &m
+}
+
+// Handler returns a handler that verifies the bearer token of the request, parses it and validates its claims
+// before calling next with the token in the request context.
+func (m BearerMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t, err := m.extractor.Extract(r)
+		if errors.Is(err, ErrTokenNotFound) {
+			// https://datatracker.ietf.org/doc/html/rfc6750#section-3.1
+			m.WriteError(w, "", "", "")
+
+			return
+		}
+
+		if err != nil {
+			m.WriteError(w, BearerErrorInvalidRequest, "invalid request", "")
+
+			return
+		}
+
+		token, err := m.Authenticate(t)
+		if err != nil {
+			m.WriteError(w, BearerErrorInvalidToken, invalidTokenDescription(err), "")
+
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(ContextWithToken(r.Context(), token)))
+	})
+}
+
+// Authenticate verifies the token string of the accepted types and validates its claims.
+func (m BearerMiddleware) Authenticate(t string) (*Token, error) {
+	token, err := m.verifier.VerifyType(t, m.types...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = token.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range m.validators {
+		err = m.validators[i](token)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return token, nil
+}
+
+// invalidTokenDescription returns a fixed error description of the error class,
+// so that verification details are not disclosed to clients.
+func invalidTokenDescription(err error) string {
+	switch {
+	case errors.Is(err, ErrTokenExpired):
+		return "token expired"
+	case errors.Is(err, ErrTokenShouldNotBeAccepted):
+		return "token not valid yet"
+	case errors.Is(err, ErrUnsupportedTokenType):
+		return "unsupported token type"
+	default:
+		return "invalid token"
+	}
+}
+
+// WriteError responds with WWW-Authenticate header of the error code and the status code it requires.
+// Empty code responds to requests without authentication information.
+// https://datatracker.ietf.org/doc/html/rfc6750#section-3
+func (m BearerMiddleware) WriteError(w http.ResponseWriter, code, description, scope string) {
+	params := make([]string, 0)
+
+	if m.realm != "" {
+		params = append(params, fmt.Sprintf("realm=%s", quoteAuthParam(m.realm)))
+	}
+
+	if code != "" {
+		params = append(params, fmt.Sprintf("error=%s", quoteAuthParam(code)))
+	}
+
+	if description != "" {
+		params = append(params, fmt.Sprintf("error_description=%s", quoteAuthParam(description)))
+	}
+
+	if scope != "" {
+		params = append(params, fmt.Sprintf("scope=%s", quoteAuthParam(scope)))
+	}
+
+	challenge := bearerScheme
+	if len(params) > 0 {
+		challenge += " " + strings.Join(params, ", ")
+	}
+
+	w.Header().Set("WWW-Authenticate", challenge)
+
+	switch code {
+	case BearerErrorInvalidRequest:
+		w.WriteHeader(http.StatusBadRequest)
+	case BearerErrorInsufficientScope:
+		w.WriteHeader(http.StatusForbidden)
+	default:
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+}
+
+// quoteAuthParam quotes auth-param value and leaves out characters not allowed in error attributes.
+// https://datatracker.ietf.org/doc/html/rfc6750#section-3
+func quoteAuthParam(value string) string {
+	return fmt.Sprintf(`"%s"`, strings.Map(func(r rune) rune {
+		if r == '"' || r == '\\' || r < ' ' || r > '~' {
+			return -1
+		}
+
+		return r
+	}, value))
+}
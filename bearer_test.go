@@ -0,0 +1,184 @@
+package jwt_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestBearerMiddleware(t *testing.T) {
+	t.Parallel()
+
+	signer, err := jwt.NewSigner(jwt.HS256, strongSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := jwt.NewVerifier(jwt.HS256, strongSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errWrongIssuer := errors.New("wrong issuer")
+
+	m := jwt.NewBearerMiddleware(*verifier, jwt.BearerWithRealm("example"), jwt.BearerWithValidator(func(token *jwt.Token) error {
+		if iss, _ := token.GetIssuer(); iss != "https://issuer.example.com" {
+			return errWrongIssuer
+		}
+
+		return nil
+	}))
+
+	handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := jwt.TokenFromContext(r.Context())
+		if !ok {
+			t.Error("excepted token in context")
+
+			return
+		}
+
+		sub, _ := token.GetSubject()
+		_, _ = w.Write([]byte(sub))
+	}))
+
+	sign := func(iss string, exp time.Time) string {
+		token := jwt.New(jwt.HS256)
+		token.SetIssuer(iss)
+		token.SetSubject("alice")
+		token.SetExpirationTime(exp)
+
+		tokenStr, err := signer.Sign(*token)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return tokenStr
+	}
+
+	valid := sign("https://issuer.example.com", time.Now().Add(time.Hour))
+
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+		challenge     string
+	}{
+		{"Valid", "Bearer " + valid, http.StatusOK, ""},
+		{"Lower Case Scheme", "bearer " + valid, http.StatusOK, ""},
+		{"Missing", "", http.StatusUnauthorized, `Bearer realm="example"`},
+		{"Basic", "Basic YWxhZGRpbjpvcGVuc2VzYW1l", http.StatusUnauthorized, `Bearer realm="example"`},
+		{"Invalid Signature", "Bearer " + valid + "x", http.StatusUnauthorized, `Bearer realm="example", error="invalid_token", error_description="invalid token"`},
+		{"Expired", "Bearer " + sign("https://issuer.example.com", time.Now().Add(-time.Hour)), http.StatusUnauthorized, `Bearer realm="example", error="invalid_token", error_description="token expired"`},
+		{"Wrong Issuer", "Bearer " + sign("https://evil.example.com", time.Now().Add(time.Hour)), http.StatusUnauthorized, `Bearer realm="example", error="invalid_token", error_description="invalid token"`},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Errorf("excepted: %d, got: %d", tt.status, w.Code)
+			}
+
+			challenge := w.Header().Get("WWW-Authenticate")
+			if !strings.HasPrefix(challenge, tt.challenge) {
+				t.Errorf("excepted: %q, got: %q", tt.challenge, challenge)
+			}
+
+			if tt.status == http.StatusOK && w.Body.String() != "alice" {
+				t.Errorf("excepted: %q, got: %q", "alice", w.Body.String())
+			}
+		})
+	}
+
+	t.Run("Error Description", func(t *testing.T) {
+		t.Parallel()
+
+		w := httptest.NewRecorder()
+		m.WriteError(w, jwt.BearerErrorInsufficientScope, `needs "write" scope`, "read write")
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("excepted: %d, got: %d", http.StatusForbidden, w.Code)
+		}
+
+		excepted := `Bearer realm="example", error="insufficient_scope", error_description="needs write scope", scope="read write"`
+		if challenge := w.Header().Get("WWW-Authenticate"); challenge != excepted {
+			t.Errorf("excepted: %q, got: %q", excepted, challenge)
+		}
+	})
+
+	t.Run("Types", func(t *testing.T) {
+		t.Parallel()
+
+		token, err := jwt.NewAccessToken(jwt.HS256)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		token.SetIssuer("https://issuer.example.com")
+		token.SetSubject("alice")
+		token.SetAudience("https://api.example.com")
+		token.SetClientID("client")
+		token.SetExpirationTime(time.Now().Add(time.Hour))
+
+		tokenStr, err := signer.SignAccessToken(*token)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_, err = m.Authenticate(tokenStr)
+		if !errors.Is(err, jwt.ErrUnsupportedTokenType) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrUnsupportedTokenType, err)
+		}
+
+		_, err = m.Authenticate(valid)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		accessTokens := jwt.NewBearerMiddleware(verifier, jwt.BearerWithTypes(jwt.TypeAccessToken))
+
+		_, err = accessTokens.Authenticate(tokenStr)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_, err = accessTokens.Authenticate(valid)
+		if !errors.Is(err, jwt.ErrUnsupportedTokenType) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrUnsupportedTokenType, err)
+		}
+	})
+
+	t.Run("Empty Context", func(t *testing.T) {
+		t.Parallel()
+
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		if _, ok := jwt.TokenFromContext(r.Context()); ok {
+			t.Error("excepted no token in context")
+		}
+	})
+}
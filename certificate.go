@@ -0,0 +1,81 @@
+package jwt
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// CertificateThumbprint returns the base64url encoded SHA-256 hash of the DER encoded certificate.
+// https://datatracker.ietf.org/doc/html/rfc8705#section-3.1
+func CertificateThumbprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+
+	return encodeBytes(sum[:])
+}
+
+// BindCertificate sets x5t#S256 confirmation of the token to the thumbprint of the client certificate.
+// Other confirmation members are kept. Tokens with an invalid cnf claim are rejected with ErrInvalidClaimType.
+func (t *Token) BindCertificate(cert *x509.Certificate) error {
+	cnf, err := t.GetConfirmation()
+	if errors.Is(err, ErrClaimNotFound) {
+		cnf, err = &Confirmation{}, nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	cnf.X509Thumbprint = CertificateThumbprint(cert)
+
+	t.SetConfirmation(*cnf)
+
+	return nil
+}
+
+// ConfirmCertificate checks x5t#S256 confirmation of the token is the thumbprint of the client certificate
+// of the mutual TLS connection.
+// https://datatracker.ietf.org/doc/html/rfc8705#section-3
+func ConfirmCertificate(token Token, state *tls.ConnectionState) error {
+	cnf, err := token.GetConfirmation()
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidConfirmation, err)
+	}
+
+	if cnf.X509Thumbprint == "" || state == nil || len(state.PeerCertificates) == 0 {
+		return ErrInvalidConfirmation
+	}
+
+	x5t := CertificateThumbprint(state.PeerCertificates[0])
+
+	if subtle.ConstantTimeCompare([]byte(x5t), []byte(cnf.X509Thumbprint)) != 1 {
+		return ErrInvalidConfirmation
+	}
+
+	return nil
+}
+
+// RequireCertificate returns a handler that requires the token in the request context to be bound to
+// the client certificate of the request. Tokens bound to other certificates are answered with invalid_token error.
+func (m BearerMiddleware) RequireCertificate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := TokenFromContext(r.Context())
+		if !ok {
+			m.WriteError(w, "", "", "")
+
+			return
+		}
+
+		if err := ConfirmCertificate(*token, r.TLS); err != nil {
+			m.WriteError(w, BearerErrorInvalidToken, "token is not bound to the client certificate", "")
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,166 @@
+package jwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestCertificateBinding(t *testing.T) {
+	t.Parallel()
+
+	clientCert := generateCertificate(t, "client")
+	otherCert := generateCertificate(t, "other")
+
+	verifier, err := jwt.NewVerifier(jwt.HS256, strongSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := jwt.NewBearerMiddleware(*verifier)
+
+	server := httptest.NewUnstartedServer(m.Handler(m.RequireCertificate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	bound := jwt.New(jwt.HS256)
+	bound.SetConfirmation(jwt.Confirmation{JWKThumbprint: "jkt"})
+
+	err = bound.BindCertificate(clientCert.Leaf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cnf, err := bound.GetConfirmation()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cnf.JWKThumbprint != "jkt" || cnf.X509Thumbprint != jwt.CertificateThumbprint(clientCert.Leaf) {
+		t.Errorf("excepted confirmation to keep jkt and set x5t#S256, got %+v", cnf)
+	}
+
+	const unboundChallenge = `Bearer error="invalid_token", error_description="token is not bound to the client certificate"`
+
+	tests := []struct {
+		name   string
+		token  *jwt.Token
+		cert   tls.Certificate
+		status int
+	}{
+		{"Bound", bound, clientCert, http.StatusNoContent},
+		{"Other Certificate", bound, otherCert, http.StatusUnauthorized},
+		{"Unbound", jwt.New(jwt.HS256), clientCert, http.StatusUnauthorized},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tokenStr, err := jwt.Sign(*tt.token, strongSecret)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			transport := server.Client().Transport.(*http.Transport).Clone()
+			transport.TLSClientConfig.Certificates = []tls.Certificate{tt.cert}
+			client := http.Client{Transport: transport}
+
+			req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			req.Header.Set("Authorization", "Bearer "+tokenStr)
+
+			res, err := client.Do(req)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			defer res.Body.Close()
+
+			if res.StatusCode != tt.status {
+				t.Errorf("excepted: %d, got: %d", tt.status, res.StatusCode)
+			}
+
+			challenge := res.Header.Get("WWW-Authenticate")
+			if tt.status == http.StatusUnauthorized && challenge != unboundChallenge {
+				t.Errorf("excepted: %q, got: %q", unboundChallenge, challenge)
+			}
+		})
+	}
+
+	t.Run("Bind Invalid Confirmation", func(t *testing.T) {
+		t.Parallel()
+
+		token := jwt.New(jwt.HS256)
+		token.Set(jwt.ClaimConfirmation, "x5t#S256")
+
+		err := token.BindCertificate(clientCert.Leaf)
+		if !errors.Is(err, jwt.ErrInvalidClaimType) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrInvalidClaimType, err)
+		}
+	})
+
+	t.Run("Without TLS", func(t *testing.T) {
+		t.Parallel()
+
+		err := jwt.ConfirmCertificate(*bound, nil)
+		if !errors.Is(err, jwt.ErrInvalidConfirmation) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrInvalidConfirmation, err)
+		}
+	})
+}
+
+// generateCertificate returns self-signed client certificate.
+func generateCertificate(t *testing.T, commonName string) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
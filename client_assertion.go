@@ -0,0 +1,183 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// ClientAssertionType is client_assertion_type parameter of jwt client assertions.
+// https://datatracker.ietf.org/doc/html/rfc7523#section-2.2
+const ClientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// Client Assertion Parameters.
+const (
+	ParameterClientAssertionType = "client_assertion_type"
+	ParameterClientAssertion     = "client_assertion"
+)
+
+// Client assertion lifetimes.
+const (
+	// DefaultClientAssertionLifetime is the lifetime of signed client assertions.
+	DefaultClientAssertionLifetime = time.Minute
+	// DefaultMaxClientAssertionLifetime is the maximum lifetime of accepted client assertions
+	// unless ClientAssertionWithMaxLifetime is set.
+	DefaultMaxClientAssertionLifetime = 5 * time.Minute
+)
+
+var ErrInvalidClientAssertionType = errors.New("invalid client assertion type")
+
+var clientAssertionClaims = []string{ClaimIssuer, ClaimSubject, ClaimAudience, ClaimExpirationTime, ClaimJWTID}
+
+// SignClientAssertion signs client assertion of the client for the token endpoint as of private_key_jwt
+// client authentication method.
+// https://datatracker.ietf.org/doc/html/rfc7523#section-3
+func (s Signer) SignClientAssertion(clientID, tokenEndpoint string) (string, error) {
+	jti, err := newJWTID()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+
+	token := New(s.alg)
+	token.SetIssuer(clientID)
+	token.SetSubject(clientID)
+	token.SetAudience(tokenEndpoint)
+	token.SetJWTID(jti)
+	token.SetIssuedAt(now)
+	token.SetExpirationTime(now.Add(DefaultClientAssertionLifetime))
+
+	return s.Sign(*token)
+}
+
+// ClientAssertionParameters returns the token request parameters of the client assertion.
+func ClientAssertionParameters(assertion string) url.Values {
+	return url.Values{
+		ParameterClientAssertionType: {ClientAssertionType},
+		ParameterClientAssertion:     {assertion},
+	}
+}
+
+// ClientAssertionFromParameters returns the client assertion of token request parameters.
+func ClientAssertionFromParameters(params url.Values) (string, error) {
+	if params.Get(ParameterClientAssertionType) != ClientAssertionType {
+		return "", ErrInvalidClientAssertionType
+	}
+
+	assertion := params.Get(ParameterClientAssertion)
+	if assertion == "" {
+		return "", ErrTokenNotFound
+	}
+
+	return assertion, nil
+}
+
+// ClientAssertionValidator validates client assertions of clients authenticating to the token endpoint.
+// https://datatracker.ietf.org/doc/html/rfc7523#section-3
+type ClientAssertionValidator struct {
+	tokenEndpoint string
+	maxLifetime   time.Duration
+	cache         ReplayCache
+}
+
+// ClientAssertionOption configures client assertion validator.
+type ClientAssertionOption func(*ClientAssertionValidator)
+
+// ClientAssertionWithMaxLifetime sets how far in the future exp of client assertions may be.
+func ClientAssertionWithMaxLifetime(maxLifetime time.Duration) ClientAssertionOption {
+	return func(v *ClientAssertionValidator) {
+		v.maxLifetime = maxLifetime
+	}
+}
+
+// ClientAssertionWithReplayCache sets the cache of used client assertion jwt ids, e.g. one shared by server instances.
+// It is a MemoryReplayCache by default.
+func ClientAssertionWithReplayCache(cache ReplayCache) ClientAssertionOption {
+	return func(v *ClientAssertionValidator) {
+		v.cache = cache
+	}
+}
+
+// NewClientAssertionValidator returns client assertion validator of the token endpoint url.
+func NewClientAssertionValidator(tokenEndpoint string, opts ...ClientAssertionOption) *ClientAssertionValidator {
+	v := ClientAssertionValidator{
+		tokenEndpoint: tokenEndpoint,
+		maxLifetime:   DefaultMaxClientAssertionLifetime,
+	}
+
+	for i := range opts {
+		opts[i](&v)
+	}
+
+	if v.cache == nil {
+		v.cache = NewMemoryReplayCache()
+	}
+
+	return &v
+}
+
+// Verify client assertion string of the client with the verifier of the client keys, validate its claims and return it.
+func (v ClientAssertionValidator) Verify(t, clientID string, verifier TokenVerifier) (*Token, error) {
+	token, err := verifier.VerifyType(t, "", typeJWT)
+	if err != nil {
+		return nil, err
+	}
+
+	err = v.Validate(*token, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// Validate the claims of client assertion verified with the keys of the client and mark its jwt id used.
+// Assertions of other clients are rejected with ErrInvalidIssuer,
+// and assertions with used jwt ids are rejected with ErrTokenReplayed.
+func (v ClientAssertionValidator) Validate(token Token, clientID string) error {
+	err := requireClaims(token, clientAssertionClaims...)
+	if err != nil {
+		return err
+	}
+
+	iss, err := token.GetIssuer()
+	if err != nil || iss != clientID {
+		return fmt.Errorf("%w: %s must be %s", ErrInvalidIssuer, ClaimIssuer, ParameterClientID)
+	}
+
+	if sub, err := token.GetSubject(); err != nil || sub != clientID {
+		return fmt.Errorf("%w: %s must be %s", ErrInvalidIssuer, ClaimSubject, ParameterClientID)
+	}
+
+	if aud, err := token.GetAudience(); err != nil || !containsAll(aud, []string{v.tokenEndpoint}) {
+		return ErrInvalidAudience
+	}
+
+	err = token.Validate()
+	if err != nil {
+		return err
+	}
+
+	exp, err := token.GetExpirationTime()
+	if err != nil {
+		return err
+	}
+
+	if exp.After(time.Now().Add(v.maxLifetime)) {
+		return fmt.Errorf("%w: %s too far in the future", ErrInvalidToken, ClaimExpirationTime)
+	}
+
+	jti, err := token.GetJWTID()
+	if err != nil {
+		return err
+	}
+
+	// jwt ids are unique per issuer
+	if !v.cache.Use(iss+" "+jti, exp) {
+		return ErrTokenReplayed
+	}
+
+	return nil
+}
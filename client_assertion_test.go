@@ -0,0 +1,128 @@
+package jwt_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestClientAssertion(t *testing.T) {
+	t.Parallel()
+
+	const (
+		clientID      = "s6BhdRkqt3"
+		tokenEndpoint = "https://as.example.com/token"
+	)
+
+	signer, err := jwt.NewSigner(jwt.RS256, []byte(private))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := jwt.NewVerifier(jwt.RS256, []byte(public))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Round Trip", func(t *testing.T) {
+		t.Parallel()
+
+		assertion, err := signer.SignClientAssertion(clientID, tokenEndpoint)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		params := jwt.ClientAssertionParameters(assertion)
+
+		received, err := jwt.ClientAssertionFromParameters(params)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		validator := jwt.NewClientAssertionValidator(tokenEndpoint)
+
+		token, err := validator.Verify(received, clientID, *verifier)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if sub, _ := token.GetSubject(); sub != clientID {
+			t.Errorf("excepted: %q, got: %q", clientID, sub)
+		}
+
+		_, err = validator.Verify(received, clientID, *verifier)
+		if !errors.Is(err, jwt.ErrTokenReplayed) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrTokenReplayed, err)
+		}
+	})
+
+	t.Run("Assertion Type", func(t *testing.T) {
+		t.Parallel()
+
+		params := jwt.ClientAssertionParameters("assertion")
+		params.Set(jwt.ParameterClientAssertionType, "urn:ietf:params:oauth:client-assertion-type:saml2-bearer")
+
+		_, err := jwt.ClientAssertionFromParameters(params)
+		if !errors.Is(err, jwt.ErrInvalidClientAssertionType) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrInvalidClientAssertionType, err)
+		}
+	})
+
+	claims := func(modify func(token *jwt.Token)) *jwt.Token {
+		token := jwt.New(jwt.RS256)
+		token.SetIssuer(clientID)
+		token.SetSubject(clientID)
+		token.SetAudience(tokenEndpoint)
+		token.SetExpirationTime(time.Now().Add(time.Minute))
+		modify(token)
+
+		return token
+	}
+
+	withJWTID := func(token *jwt.Token) { token.SetJWTID("jti-1") }
+
+	tests := []struct {
+		name  string
+		token *jwt.Token
+		err   error
+	}{
+		{"Subject", claims(func(token *jwt.Token) { withJWTID(token); token.SetSubject("other") }), jwt.ErrInvalidIssuer},
+		{"Other Client", claims(func(token *jwt.Token) {
+			withJWTID(token)
+			token.SetIssuer("other")
+			token.SetSubject("other")
+		}), jwt.ErrInvalidIssuer},
+		{"Audience", claims(func(token *jwt.Token) { withJWTID(token); token.SetAudience("https://as.example.com/authorize") }), jwt.ErrInvalidAudience},
+		{"Expired", claims(func(token *jwt.Token) { withJWTID(token); token.SetExpirationTime(time.Now().Add(-time.Minute)) }), jwt.ErrTokenExpired},
+		{"Lifetime", claims(func(token *jwt.Token) { withJWTID(token); token.SetExpirationTime(time.Now().Add(time.Hour)) }), jwt.ErrInvalidToken},
+		{"JWT ID", claims(func(token *jwt.Token) {}), jwt.ErrClaimNotFound},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tokenStr, err := signer.Sign(*tt.token)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			_, err = jwt.NewClientAssertionValidator(tokenEndpoint).Verify(tokenStr, clientID, *verifier)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("excepted error %q, got %q", tt.err, err)
+			}
+		})
+	}
+}
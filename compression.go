@@ -0,0 +1,89 @@
+package jwt
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// CompressionAlgorithm is json web encryption compression algorithm.
+type CompressionAlgorithm string
+
+// Compression Algorithms.
+const (
+	Deflate CompressionAlgorithm = "DEF"
+)
+
+// DefaultMaxDecompressedSize is the maximum decompressed plaintext size in bytes
+// unless DecryptWithMaxDecompressedSize is set.
+const DefaultMaxDecompressedSize = 1 << 20
+
+var (
+	ErrUnsupportedCompression = errors.New("unsupported compression algorithm")
+	ErrDecompressedSizeLimit  = errors.New("decompressed plaintext exceeds size limit")
+)
+
+// EncryptWithCompression compresses the plaintext with DEFLATE before encryption and sets zip header parameter.
+// https://datatracker.ietf.org/doc/html/rfc7516#section-4.1.3
+func EncryptWithCompression() EncrypterOption {
+	return func(e *Encrypter) {
+		e.header.Compression = Deflate
+	}
+}
+
+// DecryptWithMaxDecompressedSize sets the maximum decompressed plaintext size in bytes.
+func DecryptWithMaxDecompressedSize(size int64) DecrypterOption {
+	return func(d *Decrypter) {
+		d.maxDecompressedSize = size
+	}
+}
+
+func checkCompression(zip CompressionAlgorithm) error {
+	if zip != "" && zip != Deflate {
+		return ErrUnsupportedCompression
+	}
+
+	return nil
+}
+
+// https://datatracker.ietf.org/doc/html/rfc1951
+func compress(plaintext []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, fmt.Errorf("error on create compressor: %w", err)
+	}
+
+	_, err = w.Write(plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("error on compress: %w", err)
+	}
+
+	err = w.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error on compress: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decompress inflates the compressed plaintext and stops reading as soon as it exceeds the limit.
+func decompress(compressed []byte, limit int64) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(compressed))
+	defer r.Close()
+
+	plaintext, err := ioutil.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("error on decompress: %w", err)
+	}
+
+	if int64(len(plaintext)) > limit {
+		return nil, ErrDecompressedSizeLimit
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,138 @@
+package jwt_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestCompression(t *testing.T) {
+	t.Parallel()
+
+	key := strongSecret[:32]
+	plaintext := []byte(strings.Repeat(`"permission:read",`, 1000))
+
+	t.Run("Round Trip", func(t *testing.T) {
+		t.Parallel()
+
+		encrypter, err := jwt.NewEncrypter(jwt.Direct, jwt.A256GCM, key, jwt.EncryptWithCompression())
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		compressed, err := encrypter.Encrypt(plaintext)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		uncompressedEncrypter, err := jwt.NewEncrypter(jwt.Direct, jwt.A256GCM, key)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		uncompressed, err := uncompressedEncrypter.Encrypt(plaintext)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if len(compressed) >= len(uncompressed) {
+			t.Errorf("excepted compressed token shorter than %d, got: %d", len(uncompressed), len(compressed))
+		}
+
+		decrypter, err := jwt.NewDecrypter(jwt.Direct, key)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		decrypted, err := decrypter.Decrypt(compressed)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if !bytes.Equal(decrypted, plaintext) {
+			t.Errorf("excepted: %q, got: %q", plaintext, decrypted)
+		}
+	})
+
+	t.Run("Size Limit", func(t *testing.T) {
+		t.Parallel()
+
+		encrypter, err := jwt.NewEncrypter(jwt.Direct, jwt.A256GCM, key, jwt.EncryptWithCompression())
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		tokenStr, err := encrypter.Encrypt(make([]byte, jwt.DefaultMaxDecompressedSize+1))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		decrypter, err := jwt.NewDecrypter(jwt.Direct, key)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_, err = decrypter.Decrypt(tokenStr)
+		if !errors.Is(err, jwt.ErrDecompressedSizeLimit) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrDecompressedSizeLimit, err)
+		}
+
+		decrypter, err = jwt.NewDecrypter(jwt.Direct, key, jwt.DecryptWithMaxDecompressedSize(jwt.DefaultMaxDecompressedSize+1))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_, err = decrypter.Decrypt(tokenStr)
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Unsupported Compression", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		w, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+		_, _ = w.Write(plaintext)
+		_ = w.Close()
+
+		header := jwt.EncryptionHeader{Algorithm: jwt.Direct, Encryption: jwt.A256GCM, Compression: "GZIP"}
+
+		decrypter, err := jwt.NewDecrypter(jwt.Direct, key)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_, err = decrypter.Decrypt(encryptGCM(t, header, key, nil, buf.Bytes()))
+		if !errors.Is(err, jwt.ErrUnsupportedCompression) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrUnsupportedCompression, err)
+		}
+	})
+}
@@ -0,0 +1,203 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+)
+
+// ClaimConfirmation is cnf claim.
+// https://datatracker.ietf.org/doc/html/rfc7800#section-3.1
+const ClaimConfirmation = "cnf"
+
+// Confirmation is the confirmation claim binding a token to a proof-of-possession key.
+type Confirmation struct {
+	// JWK is the public proof-of-possession key.
+	// https://datatracker.ietf.org/doc/html/rfc7800#section-3.2
+	JWK *JWK `json:"jwk,omitempty"`
+	// EncryptedKey is the symmetric proof-of-possession key encrypted by JWK.Encrypt.
+	// https://datatracker.ietf.org/doc/html/rfc7800#section-3.3
+	EncryptedKey string `json:"jwe,omitempty"`
+	// KeyID is the id of a proof-of-possession key known to the recipient.
+	// https://datatracker.ietf.org/doc/html/rfc7800#section-3.4
+	KeyID string `json:"kid,omitempty"`
+	// https://datatracker.ietf.org/doc/html/rfc9449#section-6.1
+	JWKThumbprint string `json:"jkt,omitempty"`
+	// https://datatracker.ietf.org/doc/html/rfc8705#section-3.1
+	X509Thumbprint string `json:"x5t#S256,omitempty"`
+}
+
+// SetConfirmation sets cnf claim.
+func (t *Token) SetConfirmation(cnf Confirmation) {
+	t.Set(ClaimConfirmation, cnf)
+}
+
+// GetConfirmation returns cnf claim.
+func (t Token) GetConfirmation() (*Confirmation, error) {
+	value, exists := t.payload[ClaimConfirmation]
+	if !exists {
+		return nil, ErrClaimNotFound
+	}
+
+	if cnf, ok := value.(Confirmation); ok {
+		return &cnf, nil
+	}
+
+	if _, ok := value.(map[string]interface{}); !ok {
+		return nil, ErrInvalidClaimType
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("error on marshal confirmation: %w", err)
+	}
+
+	var cnf Confirmation
+
+	err = json.Unmarshal(b, &cnf)
+	if err != nil {
+		return nil, ErrInvalidClaimType
+	}
+
+	return &cnf, nil
+}
+
+// ProofOfPossession proves the presenter of a token holds the confirmed key.
+type ProofOfPossession func(key JWK) error
+
+// ConfirmationVerifier resolves the proof-of-possession key of tokens and checks the presenter holds it.
+// https://datatracker.ietf.org/doc/html/rfc7800#section-6
+type ConfirmationVerifier struct {
+	decrypter *Decrypter
+	keySet    JWKSet
+}
+
+// ConfirmationVerifierOption configures confirmation verifier.
+type ConfirmationVerifierOption func(*ConfirmationVerifier)
+
+// ConfirmationWithDecrypter sets the decrypter of jwe confirmation members.
+func ConfirmationWithDecrypter(decrypter Decrypter) ConfirmationVerifierOption {
+	return func(v *ConfirmationVerifier) {
+		v.decrypter = &decrypter
+	}
+}
+
+// ConfirmationWithKeySet sets the keys known to the recipient referenced by kid confirmation members.
+func ConfirmationWithKeySet(set JWKSet) ConfirmationVerifierOption {
+	return func(v *ConfirmationVerifier) {
+		v.keySet = set
+	}
+}
+
+// NewConfirmationVerifier returns confirmation verifier.
+func NewConfirmationVerifier(opts ...ConfirmationVerifierOption) *ConfirmationVerifier {
+	var v ConfirmationVerifier
+
+	for i := range opts {
+		opts[i](&v)
+	}
+
+	return &v
+}
+
+// Key returns the proof-of-possession key of the token from its jwk, jwe or kid confirmation member.
+// The key must match jkt confirmation member when it is also present.
+func (v ConfirmationVerifier) Key(token Token) (*JWK, error) {
+	cnf, err := token.GetConfirmation()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidConfirmation, err)
+	}
+
+	key, err := v.resolve(*cnf)
+	if err != nil {
+		return nil, err
+	}
+
+	if cnf.JWKThumbprint != "" {
+		jkt, err := key.Thumbprint(crypto.SHA256)
+		if err != nil {
+			return nil, err
+		}
+
+		if subtle.ConstantTimeCompare([]byte(jkt), []byte(cnf.JWKThumbprint)) != 1 {
+			return nil, ErrInvalidConfirmation
+		}
+	}
+
+	return key, nil
+}
+
+func (v ConfirmationVerifier) resolve(cnf Confirmation) (*JWK, error) {
+	switch {
+	case cnf.JWK != nil:
+		if cnf.JWK.IsPrivate() {
+			return nil, fmt.Errorf("%w: jwk holds private key", ErrInvalidConfirmation)
+		}
+
+		return cnf.JWK, nil
+	case cnf.EncryptedKey != "":
+		if v.decrypter == nil {
+			return nil, fmt.Errorf("%w: no decrypter of jwe", ErrInvalidConfirmation)
+		}
+
+		key, err := DecryptJWK(cnf.EncryptedKey, *v.decrypter)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidConfirmation, err)
+		}
+
+		return key, nil
+	case cnf.KeyID != "":
+		return v.keySet.LookupKeyID(cnf.KeyID)
+	default:
+		return nil, ErrKeyNotFound
+	}
+}
+
+// Confirm resolves the proof-of-possession key of the token and calls the proof with it.
+func (v ConfirmationVerifier) Confirm(token Token, proof ProofOfPossession) error {
+	key, err := v.Key(token)
+	if err != nil {
+		return err
+	}
+
+	err = proof(*key)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidConfirmation, err)
+	}
+
+	return nil
+}
+
+// ChallengeProof returns proof of possession by a token signed with the confirmed key
+// with nonce claim of the challenge issued to the presenter.
+// The token algorithm must be the key algorithm when the key has one.
+func ChallengeProof(signedChallenge, challenge string) ProofOfPossession {
+	return func(key JWK) error {
+		if challenge == "" {
+			return ErrInvalidNonce
+		}
+
+		token, err := Parse(signedChallenge)
+		if err != nil {
+			return err
+		}
+
+		alg := token.header.Algorithm
+		if key.Algorithm != "" && Algorithm(key.Algorithm) != alg {
+			return ErrUnsupportedAlgorithm
+		}
+
+		verifier, err := newJWKVerifier(key, alg, nil)
+		if err != nil {
+			return err
+		}
+
+		err = verifier.Verify(signedChallenge)
+		if err != nil {
+			return err
+		}
+
+		return matchClaim(*token, ClaimNonce, challenge, challenge, ErrInvalidNonce)
+	}
+}
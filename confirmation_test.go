@@ -0,0 +1,139 @@
+package jwt_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestConfirmation(t *testing.T) {
+	t.Parallel()
+
+	const challenge = "n-0S6_WzA2Mj"
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicJWK, err := jwt.NewJWK(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := jwt.NewCryptoSigner(jwt.ES256, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signedChallenge := signChallenge(t, *signer, challenge)
+
+	symmetricKey := []byte("fedcba9876543210fedcba9876543210")
+
+	symmetricJWK, err := jwt.NewJWK(symmetricKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	symmetricSigner, err := jwt.NewSigner(jwt.HS256, symmetricKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypter, err := jwt.NewEncrypter(jwt.A256KW, jwt.A256GCM, strongSecret[:32])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decrypter, err := jwt.NewDecrypter(jwt.A256KW, strongSecret[:32])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encryptedKey, err := symmetricJWK.Encrypt(*encrypter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jkt, err := publicJWK.Thumbprint(crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyID := *publicJWK
+	keyID.KeyID = "pop-1"
+
+	verifier := jwt.NewConfirmationVerifier(
+		jwt.ConfirmationWithDecrypter(*decrypter),
+		jwt.ConfirmationWithKeySet(jwt.JWKSet{Keys: []jwt.JWK{keyID}}),
+	)
+
+	tests := []struct {
+		name      string
+		cnf       jwt.Confirmation
+		verifier  *jwt.ConfirmationVerifier
+		challenge string
+		err       error
+	}{
+		{"JWK", jwt.Confirmation{JWK: publicJWK, JWKThumbprint: jkt}, verifier, signedChallenge, nil},
+		{"JWE", jwt.Confirmation{EncryptedKey: encryptedKey}, verifier, signChallenge(t, *symmetricSigner, challenge), nil},
+		{"Key ID", jwt.Confirmation{KeyID: "pop-1"}, verifier, signedChallenge, nil},
+		{"Unknown Key ID", jwt.Confirmation{KeyID: "pop-2"}, verifier, signedChallenge, jwt.ErrKeyNotFound},
+		{"JWE Without Decrypter", jwt.Confirmation{EncryptedKey: encryptedKey}, jwt.NewConfirmationVerifier(), signedChallenge, jwt.ErrInvalidConfirmation},
+		{"Thumbprint Mismatch", jwt.Confirmation{JWK: publicJWK, JWKThumbprint: "other"}, verifier, signedChallenge, jwt.ErrInvalidConfirmation},
+		{"Private JWK", jwt.Confirmation{JWK: symmetricJWK}, verifier, signedChallenge, jwt.ErrInvalidConfirmation},
+		{"Other Key", jwt.Confirmation{JWK: publicJWK}, verifier, signChallenge(t, *symmetricSigner, challenge), jwt.ErrInvalidConfirmation},
+		{"Other Challenge", jwt.Confirmation{JWK: publicJWK}, verifier, signChallenge(t, *signer, "other"), jwt.ErrInvalidConfirmation},
+		{"No Key", jwt.Confirmation{JWKThumbprint: jkt}, verifier, signedChallenge, jwt.ErrKeyNotFound},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			token := jwt.New(jwt.HS256)
+			token.SetConfirmation(tt.cnf)
+
+			tokenStr, err := jwt.Sign(*token, strongSecret)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			token, err = jwt.Parse(tokenStr)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			err = tt.verifier.Confirm(*token, jwt.ChallengeProof(tt.challenge, challenge))
+			if !errors.Is(err, tt.err) {
+				t.Errorf("excepted error %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+// signChallenge returns token with the challenge in nonce claim.
+func signChallenge(t *testing.T, signer jwt.Signer, challenge string) string {
+	t.Helper()
+
+	token := jwt.New(signer.Algorithm())
+	token.Set(jwt.ClaimNonce, challenge)
+
+	tokenStr, err := signer.Sign(*token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tokenStr
+}
@@ -0,0 +1,73 @@
+package jwt
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SignDetached signs the payload and returns compact json web signature with detached payload, header..signature.
+// https://datatracker.ietf.org/doc/html/rfc7515#appendix-F
+func (s Signer) SignDetached(payload []byte) (string, error) {
+	return s.signDetached(Header{Algorithm: s.alg, KeyID: s.keyID}, base64.RawURLEncoding.EncodeToString(payload))
+}
+
+// SignUnencoded signs the raw payload bytes instead of their base64url encoding
+// and returns compact json web signature with detached payload.
+// https://datatracker.ietf.org/doc/html/rfc7797
+func (s Signer) SignUnencoded(payload []byte) (string, error) {
+	b64 := false
+
+	header := Header{
+		Algorithm: s.alg,
+		KeyID:     s.keyID,
+		Critical:  []string{HeaderBase64},
+		Base64:    &b64,
+	}
+
+	return s.signDetached(header, string(payload))
+}
+
+func (s Signer) signDetached(header Header, payload string) (string, error) {
+	headerBytes, err := json.Marshal(header)
+	if err != nil {
+		return "", fmt.Errorf("error on marshal header: %w", err)
+	}
+
+	encodedHeader := base64.RawURLEncoding.EncodeToString(headerBytes)
+
+	signature, err := signWithKey(context.Background(), s.alg, s.key, []byte(fmt.Sprintf("%s.%s", encodedHeader, payload)))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s..%s", encodedHeader, base64.RawURLEncoding.EncodeToString(signature)), nil
+}
+
+// VerifyDetached verifies compact json web signature with detached payload against the payload.
+// Both base64url encoded and unencoded (b64 false) payloads are supported.
+func (v Verifier) VerifyDetached(t string, payload []byte) error {
+	arr := strings.Split(t, ".")
+	if len(arr) != tokenParts || arr[1] != "" {
+		return ErrInvalidToken
+	}
+
+	header, err := decodeJOSEHeader(arr[0])
+	if err != nil {
+		return err
+	}
+
+	encodedPayload := string(payload)
+	if header.isPayloadEncoded() {
+		encodedPayload = base64.RawURLEncoding.EncodeToString(payload)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(arr[2])
+	if err != nil {
+		return fmt.Errorf("invalid token signature encoding: %w", err)
+	}
+
+	return v.verifySignature(header, []byte(fmt.Sprintf("%s.%s", arr[0], encodedPayload)), signature)
+}
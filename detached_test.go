@@ -0,0 +1,117 @@
+package jwt_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestDetached(t *testing.T) {
+	t.Parallel()
+
+	// https://datatracker.ietf.org/doc/html/rfc7797#section-4
+	key, err := base64.RawURLEncoding.DecodeString("AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := []byte("$.02")
+
+	signer, err := jwt.NewSigner(jwt.HS256, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := jwt.NewVerifier(jwt.HS256, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Sign detached", func(t *testing.T) {
+		t.Parallel()
+
+		excepted := "eyJhbGciOiJIUzI1NiJ9..5mvfOroL-g7HyqJoozehmsaqmvTYGEq5jTI1gVvoEoQ"
+
+		tokenStr, err := signer.SignDetached(payload)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if tokenStr != excepted {
+			t.Errorf("excepted: %q, got: %q", excepted, tokenStr)
+		}
+
+		err = verifier.VerifyDetached(tokenStr, payload)
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Sign unencoded", func(t *testing.T) {
+		t.Parallel()
+
+		excepted := "eyJhbGciOiJIUzI1NiIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19..A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY"
+
+		tokenStr, err := signer.SignUnencoded(payload)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if tokenStr != excepted {
+			t.Errorf("excepted: %q, got: %q", excepted, tokenStr)
+		}
+
+		err = verifier.VerifyDetached(tokenStr, payload)
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Verify modified payload", func(t *testing.T) {
+		t.Parallel()
+
+		tokenStr := "eyJhbGciOiJIUzI1NiIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19..A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY"
+
+		err := verifier.VerifyDetached(tokenStr, []byte("$.03"))
+		if !errors.Is(err, jwt.ErrInvalidTokenSignature) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Verify attached payload", func(t *testing.T) {
+		t.Parallel()
+
+		tokenStr := "eyJhbGciOiJIUzI1NiJ9.JC4wMg.5mvfOroL-g7HyqJoozehmsaqmvTYGEq5jTI1gVvoEoQ"
+
+		err := verifier.VerifyDetached(tokenStr, payload)
+		if !errors.Is(err, jwt.ErrInvalidToken) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Verify b64 without crit", func(t *testing.T) {
+		t.Parallel()
+
+		// {"alg":"HS256","b64":false}
+		tokenStr := "eyJhbGciOiJIUzI1NiIsImI2NCI6ZmFsc2V9..A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY"
+
+		err := verifier.VerifyDetached(tokenStr, payload)
+		if !errors.Is(err, jwt.ErrInvalidToken) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Verify unknown crit", func(t *testing.T) {
+		t.Parallel()
+
+		// {"alg":"HS256","crit":["exp"],"exp":1}
+		tokenStr := "eyJhbGciOiJIUzI1NiIsImNyaXQiOlsiZXhwIl0sImV4cCI6MX0..A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY"
+
+		err := verifier.VerifyDetached(tokenStr, payload)
+		if !errors.Is(err, jwt.ErrInvalidToken) {
+			t.Error(err)
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	wellKnownOpenIDConfiguration = "/.well-known/openid-configuration"
+	maxMetadataSize              = 1 << 20
+)
+
+// ErrUnexpectedStatus is returned when metadata or key set endpoints respond other than 200 OK.
+var ErrUnexpectedStatus = errors.New("unexpected http response status")
+
+// ProviderMetadata is OpenID provider metadata.
+// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata
+type ProviderMetadata struct {
+	Issuer                           string      `json:"issuer"`
+	AuthorizationEndpoint            string      `json:"authorization_endpoint,omitempty"`
+	TokenEndpoint                    string      `json:"token_endpoint,omitempty"`
+	UserinfoEndpoint                 string      `json:"userinfo_endpoint,omitempty"`
+	JWKSURI                          string      `json:"jwks_uri"`
+	ScopesSupported                  []string    `json:"scopes_supported,omitempty"`
+	ResponseTypesSupported           []string    `json:"response_types_supported,omitempty"`
+	SubjectTypesSupported            []string    `json:"subject_types_supported,omitempty"`
+	IDTokenSigningAlgValuesSupported []Algorithm `json:"id_token_signing_alg_values_supported,omitempty"`
+	ClaimsSupported                  []string    `json:"claims_supported,omitempty"`
+}
+
+// Provider is OpenID provider discovered by its issuer.
+type Provider struct {
+	metadata ProviderMetadata
+	keySet   JWKSet
+}
+
+// DiscoveryOption configures discovery.
+type DiscoveryOption func(*discovery)
+
+type discovery struct {
+	client *http.Client
+}
+
+// DiscoverWithHTTPClient sets the client of metadata and key set requests. It is http.DefaultClient by default.
+func DiscoverWithHTTPClient(client *http.Client) DiscoveryOption {
+	return func(d *discovery) {
+		d.client = client
+	}
+}
+
+// Discover fetches OpenID provider metadata of the issuer and its json web key set.
+// The issuer of the metadata must be exactly the issuer.
+// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderConfig
+func Discover(ctx context.Context, issuer string, opts ...DiscoveryOption) (*Provider, error) {
+	d := discovery{
+		client: http.DefaultClient,
+	}
+
+	for i := range opts {
+		opts[i](&d)
+	}
+
+	var p Provider
+
+	err := d.get(ctx, strings.TrimSuffix(issuer, "/")+wellKnownOpenIDConfiguration, &p.metadata)
+	if err != nil {
+		return nil, fmt.Errorf("error on fetch provider metadata: %w", err)
+	}
+
+	// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderConfigurationValidation
+	if p.metadata.Issuer != issuer {
+		return nil, ErrInvalidIssuer
+	}
+
+	if p.metadata.JWKSURI == "" {
+		return nil, fmt.Errorf("error on fetch provider metadata: %w: jwks_uri", ErrClaimNotFound)
+	}
+
+	err = d.get(ctx, p.metadata.JWKSURI, &p.keySet)
+	if err != nil {
+		return nil, fmt.Errorf("error on fetch key set: %w", err)
+	}
+
+	return &p, nil
+}
+
+func (d discovery) get(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %d", ErrUnexpectedStatus, res.StatusCode)
+	}
+
+	return json.NewDecoder(io.LimitReader(res.Body, maxMetadataSize)).Decode(v)
+}
+
+// Metadata returns the provider metadata.
+func (p Provider) Metadata() ProviderMetadata {
+	return p.metadata
+}
+
+// KeySet returns the provider json web key set.
+func (p Provider) KeySet() JWKSet {
+	return p.keySet
+}
+
+// Algorithms returns the supported ID token signing algorithms. It is RS256 if the provider does not list any.
+// Unsecured none algorithm is never returned.
+func (p Provider) Algorithms() []Algorithm {
+	algs := make([]Algorithm, 0, len(p.metadata.IDTokenSigningAlgValuesSupported))
+
+	for _, alg := range p.metadata.IDTokenSigningAlgValuesSupported {
+		if isSupported(alg) {
+			algs = append(algs, alg)
+		}
+	}
+
+	if len(p.metadata.IDTokenSigningAlgValuesSupported) == 0 {
+		algs = append(algs, RS256)
+	}
+
+	return algs
+}
+
+// Verifier returns a verifier of the provider key set that accepts only the provider algorithms.
+func (p Provider) Verifier(opts ...VerifierOption) *KeySetVerifier {
+	return NewKeySetVerifier(p.keySet, p.Algorithms(), opts...)
+}
+
+// IDTokenValidator returns ID token validator of the provider issuer and the client.
+func (p Provider) IDTokenValidator(clientID string, opts ...IDTokenOption) *IDTokenValidator {
+	return NewIDTokenValidator(p.metadata.Issuer, clientID, opts...)
+}
@@ -0,0 +1,158 @@
+package jwt_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestDiscover(t *testing.T) {
+	t.Parallel()
+
+	var keys jwt.JWKSet
+
+	for _, alg := range []jwt.Algorithm{jwt.RS256, jwt.ES256} {
+		key, err := jwt.GenerateKey(alg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		jwk, err := key.JWK()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		jwk.KeyID = string(alg)
+		keys.Keys = append(keys.Keys, *jwk)
+	}
+
+	var issuer string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jwt.ProviderMetadata{
+			Issuer:                           issuer,
+			JWKSURI:                          issuer + "/jwks",
+			IDTokenSigningAlgValuesSupported: []jwt.Algorithm{jwt.RS256, "none"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(keys.Public())
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	issuer = server.URL
+
+	provider, err := jwt.Discover(context.Background(), issuer, jwt.DiscoverWithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idToken := func(jwk jwt.JWK) string {
+		signer, err := jwt.NewJWKSigner(jwk)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		token := jwt.New(signer.Algorithm())
+		token.SetIssuer(issuer)
+		token.SetSubject("alice")
+		token.SetAudience("client")
+		token.SetIssuedAt(time.Now())
+		token.SetExpirationTime(time.Now().Add(time.Hour))
+
+		tokenStr, err := signer.Sign(*token)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return tokenStr
+	}
+
+	t.Run("Verify ID Token", func(t *testing.T) {
+		t.Parallel()
+
+		algs := provider.Algorithms()
+		if len(algs) != 1 || algs[0] != jwt.RS256 {
+			t.Errorf("excepted: %q, got: %q", []jwt.Algorithm{jwt.RS256}, algs)
+		}
+
+		claims, err := provider.IDTokenValidator("client").Verify(idToken(keys.Keys[0]), provider.Verifier())
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if claims.Subject != "alice" {
+			t.Errorf("excepted: %q, got: %q", "alice", claims.Subject)
+		}
+	})
+
+	t.Run("Algorithm Not Allowed", func(t *testing.T) {
+		t.Parallel()
+
+		err := provider.Verifier().Verify(idToken(keys.Keys[1]))
+		if !errors.Is(err, jwt.ErrUnsupportedAlgorithm) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrUnsupportedAlgorithm, err)
+		}
+	})
+
+	t.Run("Unknown Key", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := jwt.GenerateKey(jwt.RS256)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		jwk, err := key.JWK()
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		jwk.KeyID = "unknown"
+
+		err = provider.Verifier().Verify(idToken(*jwk))
+		if !errors.Is(err, jwt.ErrKeyNotFound) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrKeyNotFound, err)
+		}
+
+		jwk.KeyID = string(jwt.RS256)
+
+		err = provider.Verifier().Verify(idToken(*jwk))
+		if !errors.Is(err, jwt.ErrInvalidTokenSignature) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrInvalidTokenSignature, err)
+		}
+	})
+
+	t.Run("Issuer Mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := jwt.Discover(context.Background(), issuer+"/", jwt.DiscoverWithHTTPClient(server.Client()))
+		if !errors.Is(err, jwt.ErrInvalidIssuer) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrInvalidIssuer, err)
+		}
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := jwt.Discover(context.Background(), issuer+"/tenant", jwt.DiscoverWithHTTPClient(server.Client()))
+		if !errors.Is(err, jwt.ErrUnexpectedStatus) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrUnexpectedStatus, err)
+		}
+	})
+}
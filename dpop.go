@@ -0,0 +1,335 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TypeDPoP is typ header parameter of DPoP proofs.
+// https://datatracker.ietf.org/doc/html/rfc9449#section-4.2
+const TypeDPoP = "dpop+jwt"
+
+// DPoP Proof Claim Names.
+const (
+	ClaimHTTPMethod = "htm"
+	ClaimHTTPURI    = "htu"
+	// ClaimAccessTokenHashDPoP is ath claim, the base64url encoded SHA-256 hash of the access token.
+	ClaimAccessTokenHashDPoP = "ath"
+)
+
+// DPoP proof freshness.
+const (
+	// DefaultDPoPMaxAge is the maximum age of DPoP proofs unless DPoPValidatorWithMaxAge is set.
+	DefaultDPoPMaxAge = time.Minute
+	// dpopClockSkew is how far in the future DPoP proofs may be issued.
+	dpopClockSkew = 5 * time.Second
+)
+
+var (
+	ErrInvalidDPoPProof    = errors.New("invalid dpop proof")
+	ErrUseDPoPNonce        = errors.New("dpop proof nonce required")
+	ErrTokenReplayed       = errors.New("token replayed")
+	ErrInvalidConfirmation = errors.New("token confirmation does not match proof-of-possession key")
+)
+
+type dpopProof struct {
+	accessToken string
+	nonce       string
+}
+
+// DPoPOption configures DPoP proof.
+type DPoPOption func(*dpopProof)
+
+// DPoPWithAccessToken sets ath claim of the access token the proof is presented with.
+func DPoPWithAccessToken(accessToken string) DPoPOption {
+	return func(p *dpopProof) {
+		p.accessToken = accessToken
+	}
+}
+
+// DPoPWithNonce sets nonce claim of the nonce provided by the server.
+// https://datatracker.ietf.org/doc/html/rfc9449#section-8
+func DPoPWithNonce(nonce string) DPoPOption {
+	return func(p *dpopProof) {
+		p.nonce = nonce
+	}
+}
+
+// SignDPoPProof signs DPoP proof of the http request with the public key of the signer embedded in jwk header parameter.
+// https://datatracker.ietf.org/doc/html/rfc9449#section-4.2
+func (s Signer) SignDPoPProof(method, uri string, opts ...DPoPOption) (string, error) {
+	signer, ok := s.key.(ContextSigner)
+	if !ok {
+		return "", ErrInvalidKey
+	}
+
+	jwk, err := NewJWK(signer.Public())
+	if err != nil {
+		return "", err
+	}
+
+	var p dpopProof
+
+	for i := range opts {
+		opts[i](&p)
+	}
+
+	htu, err := normalizeHTU(uri)
+	if err != nil {
+		return "", err
+	}
+
+	jti, err := newJWTID()
+	if err != nil {
+		return "", err
+	}
+
+	token := New(s.alg)
+	token.header.Type = TypeDPoP
+	token.header.JWK = jwk
+	token.SetJWTID(jti)
+	token.Set(ClaimHTTPMethod, method)
+	token.Set(ClaimHTTPURI, htu)
+	token.SetIssuedAt(time.Now())
+
+	if p.accessToken != "" {
+		token.Set(ClaimAccessTokenHashDPoP, accessTokenHash(p.accessToken))
+	}
+
+	if p.nonce != "" {
+		token.Set(ClaimNonce, p.nonce)
+	}
+
+	return s.Sign(*token)
+}
+
+// DPoPValidator validates DPoP proofs.
+// https://datatracker.ietf.org/doc/html/rfc9449#section-4.3
+type DPoPValidator struct {
+	algs   []Algorithm
+	maxAge time.Duration
+	cache  ReplayCache
+}
+
+// DPoPValidatorOption configures DPoP validator.
+type DPoPValidatorOption func(*DPoPValidator)
+
+// DPoPValidatorWithAlgorithms sets the accepted algorithms. They are all asymmetric algorithms by default.
+func DPoPValidatorWithAlgorithms(algs ...Algorithm) DPoPValidatorOption {
+	return func(v *DPoPValidator) {
+		v.algs = algs
+	}
+}
+
+// DPoPValidatorWithMaxAge sets the maximum age of proofs by their iat claim.
+func DPoPValidatorWithMaxAge(maxAge time.Duration) DPoPValidatorOption {
+	return func(v *DPoPValidator) {
+		v.maxAge = maxAge
+	}
+}
+
+// DPoPValidatorWithReplayCache sets the cache of used proof jwt ids, e.g. one shared by server instances.
+// It is a MemoryReplayCache by default.
+func DPoPValidatorWithReplayCache(cache ReplayCache) DPoPValidatorOption {
+	return func(v *DPoPValidator) {
+		v.cache = cache
+	}
+}
+
+// NewDPoPValidator returns DPoP proof validator.
+func NewDPoPValidator(opts ...DPoPValidatorOption) *DPoPValidator {
+	v := DPoPValidator{
+		algs:   []Algorithm{RS256, RS384, RS512, ES256, ES384, ES512, PS256, PS384, PS512, EdDSA},
+		maxAge: DefaultDPoPMaxAge,
+	}
+
+	for i := range opts {
+		opts[i](&v)
+	}
+
+	if v.cache == nil {
+		v.cache = NewMemoryReplayCache()
+	}
+
+	return &v
+}
+
+// Validate DPoP proof of the http request method and uri and return it.
+// With DPoPWithAccessToken the ath claim must match the access token and
+// with DPoPWithNonce the nonce claim must match the nonce, otherwise ErrUseDPoPNonce is returned.
+func (v DPoPValidator) Validate(proof, method, uri string, opts ...DPoPOption) (*Token, error) {
+	var p dpopProof
+
+	for i := range opts {
+		opts[i](&p)
+	}
+
+	token, err := v.verify(proof)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDPoPProof, err)
+	}
+
+	err = requireClaims(*token, ClaimJWTID, ClaimHTTPMethod, ClaimHTTPURI, ClaimIssuedAt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDPoPProof, err)
+	}
+
+	if htm, _ := token.Get(ClaimHTTPMethod); htm != method {
+		return nil, fmt.Errorf("%w: %s mismatch", ErrInvalidDPoPProof, ClaimHTTPMethod)
+	}
+
+	expectedHTU, err := normalizeHTU(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	htu, _ := token.Get(ClaimHTTPURI)
+	if s, _ := htu.(string); !isHTU(s, expectedHTU) {
+		return nil, fmt.Errorf("%w: %s mismatch", ErrInvalidDPoPProof, ClaimHTTPURI)
+	}
+
+	err = matchClaim(*token, ClaimAccessTokenHashDPoP, p.accessToken, accessTokenHash(p.accessToken), ErrInvalidDPoPProof)
+	if err != nil {
+		return nil, err
+	}
+
+	err = matchClaim(*token, ClaimNonce, p.nonce, p.nonce, ErrUseDPoPNonce)
+	if err != nil {
+		return nil, err
+	}
+
+	iat, err := token.GetIssuedAt()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDPoPProof, err)
+	}
+
+	now := time.Now()
+	if iat.Before(now.Add(-v.maxAge)) || iat.After(now.Add(dpopClockSkew)) {
+		return nil, fmt.Errorf("%w: %s out of range", ErrInvalidDPoPProof, ClaimIssuedAt)
+	}
+
+	jti, err := token.GetJWTID()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDPoPProof, err)
+	}
+
+	if !v.cache.Use(jti, iat.Add(v.maxAge+dpopClockSkew)) {
+		return nil, ErrTokenReplayed
+	}
+
+	return token, nil
+}
+
+// verify the proof signature with its jwk header parameter.
+func (v DPoPValidator) verify(proof string) (*Token, error) {
+	token, err := Parse(proof)
+	if err != nil {
+		return nil, err
+	}
+
+	alg := token.header.Algorithm
+	if !containsAll(algorithmNames(v.algs), []string{string(alg)}) || isHMAC(alg) {
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	if token.header.JWK == nil || token.header.JWK.IsPrivate() {
+		return nil, ErrInvalidKey
+	}
+
+	key, err := token.header.JWK.Key()
+	if err != nil {
+		return nil, err
+	}
+
+	verifier, err := NewPublicKeyVerifier(alg, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return verifier.VerifyType(proof, TypeDPoP)
+}
+
+// ConfirmDPoPKey checks jkt confirmation claim of the access token is the thumbprint of the validated DPoP proof key.
+// https://datatracker.ietf.org/doc/html/rfc9449#section-6
+func ConfirmDPoPKey(proof, accessToken Token) error {
+	cnf, err := accessToken.GetConfirmation()
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidConfirmation, err)
+	}
+
+	if proof.header.JWK == nil || cnf.JWKThumbprint == "" {
+		return ErrInvalidConfirmation
+	}
+
+	jkt, err := proof.header.JWK.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(jkt), []byte(cnf.JWKThumbprint)) != 1 {
+		return ErrInvalidConfirmation
+	}
+
+	return nil
+}
+
+// matchClaim requires string claim to be the expected value when the option value is set.
+func matchClaim(token Token, claim, option, expected string, errMismatch error) error {
+	if option == "" {
+		return nil
+	}
+
+	value, _ := token.Get(claim)
+
+	if s, ok := value.(string); !ok || subtle.ConstantTimeCompare([]byte(s), []byte(expected)) != 1 {
+		return fmt.Errorf("%w: %s mismatch", errMismatch, claim)
+	}
+
+	return nil
+}
+
+// https://datatracker.ietf.org/doc/html/rfc9449#section-4.2
+func accessTokenHash(accessToken string) string {
+	sum := sha256.Sum256([]byte(accessToken))
+
+	return encodeBytes(sum[:])
+}
+
+// normalizeHTU returns the uri without query and fragment and with lower case scheme and host and without default port.
+// https://datatracker.ietf.org/doc/html/rfc9449#section-4.3
+func normalizeHTU(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("invalid uri: %w", err)
+	}
+
+	if !u.IsAbs() || u.Host == "" {
+		return "", fmt.Errorf("%w: %s is not absolute", ErrInvalidDPoPProof, uri)
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Host)
+
+	if (scheme == "https" && strings.HasSuffix(host, ":443")) || (scheme == "http" && strings.HasSuffix(host, ":80")) {
+		host = host[:strings.LastIndex(host, ":")]
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	return scheme + "://" + host + path, nil
+}
+
+func isHTU(htu, expected string) bool {
+	normalized, err := normalizeHTU(htu)
+
+	return err == nil && normalized == expected
+}
@@ -0,0 +1,209 @@
+package jwt_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestDPoP(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := jwt.NewCryptoSigner(jwt.ES256, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		method      = "POST"
+		uri         = "https://server.example.com/token"
+		accessToken = "Kz~8mXK1EalYznwH-LC-1fBAo.4Ljp~zsPE_NeO.gxU"
+	)
+
+	t.Run("Round Trip", func(t *testing.T) {
+		t.Parallel()
+
+		proof, err := signer.SignDPoPProof(method, "HTTPS://Server.Example.com:443/token?foo=bar", jwt.DPoPWithAccessToken(accessToken), jwt.DPoPWithNonce("eyJ7S_zG.eyJH0-Z.HX4w-7v"))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		token, err := jwt.NewDPoPValidator().Validate(proof, method, uri, jwt.DPoPWithAccessToken(accessToken), jwt.DPoPWithNonce("eyJ7S_zG.eyJH0-Z.HX4w-7v"))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if typ := token.GetHeader().Type; typ != jwt.TypeDPoP {
+			t.Errorf("excepted: %q, got: %q", jwt.TypeDPoP, typ)
+		}
+
+		// https://datatracker.ietf.org/doc/html/rfc9449#section-7.1
+		if ath, _ := token.Get(jwt.ClaimAccessTokenHashDPoP); ath != "fUHyO2r2Z3DZ53EsNrWBb0xWXoaNy59IiKCAqksmQEo" {
+			t.Errorf("excepted: %q, got: %q", "fUHyO2r2Z3DZ53EsNrWBb0xWXoaNy59IiKCAqksmQEo", ath)
+		}
+	})
+
+	t.Run("Invalid Proof", func(t *testing.T) {
+		t.Parallel()
+
+		proof, err := signer.SignDPoPProof(method, uri, jwt.DPoPWithAccessToken(accessToken))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		tests := []struct {
+			name   string
+			method string
+			uri    string
+			opts   []jwt.DPoPOption
+			err    error
+		}{
+			{name: "Method", method: "GET", uri: uri, err: jwt.ErrInvalidDPoPProof},
+			{name: "URI", method: method, uri: "https://server.example.com/resource", err: jwt.ErrInvalidDPoPProof},
+			{name: "Access Token", method: method, uri: uri, opts: []jwt.DPoPOption{jwt.DPoPWithAccessToken("other")}, err: jwt.ErrInvalidDPoPProof},
+			{name: "Nonce", method: method, uri: uri, opts: []jwt.DPoPOption{jwt.DPoPWithNonce("nonce")}, err: jwt.ErrUseDPoPNonce},
+		}
+
+		for i := range tests {
+			tt := tests[i]
+
+			_, err := jwt.NewDPoPValidator().Validate(proof, tt.method, tt.uri, tt.opts...)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("%s: excepted error %q, got %q", tt.name, tt.err, err)
+			}
+		}
+	})
+
+	t.Run("Replay", func(t *testing.T) {
+		t.Parallel()
+
+		proof, err := signer.SignDPoPProof(method, uri)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		validator := jwt.NewDPoPValidator()
+
+		_, err = validator.Validate(proof, method, uri)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_, err = validator.Validate(proof, method, uri)
+		if !errors.Is(err, jwt.ErrTokenReplayed) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrTokenReplayed, err)
+		}
+	})
+
+	t.Run("Stale", func(t *testing.T) {
+		t.Parallel()
+
+		proof, err := signer.SignDPoPProof(method, uri)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_, err = jwt.NewDPoPValidator(jwt.DPoPValidatorWithMaxAge(-time.Second)).Validate(proof, method, uri)
+		if !errors.Is(err, jwt.ErrInvalidDPoPProof) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrInvalidDPoPProof, err)
+		}
+	})
+
+	t.Run("Algorithm", func(t *testing.T) {
+		t.Parallel()
+
+		proof, err := signer.SignDPoPProof(method, uri)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_, err = jwt.NewDPoPValidator(jwt.DPoPValidatorWithAlgorithms(jwt.EdDSA)).Validate(proof, method, uri)
+		if !errors.Is(err, jwt.ErrInvalidDPoPProof) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrInvalidDPoPProof, err)
+		}
+
+		hmacSigner, err := jwt.NewSigner(jwt.HS256, strongSecret)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_, err = hmacSigner.SignDPoPProof(method, uri)
+		if !errors.Is(err, jwt.ErrInvalidKey) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrInvalidKey, err)
+		}
+	})
+
+	t.Run("Confirm Key", func(t *testing.T) {
+		t.Parallel()
+
+		proofStr, err := signer.SignDPoPProof(method, uri)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		proof, err := jwt.NewDPoPValidator().Validate(proofStr, method, uri)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		jwk, err := jwt.NewJWK(&key.PublicKey)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		jkt, err := jwk.Thumbprint(crypto.SHA256)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		token := jwt.New(jwt.RS256)
+		token.SetConfirmation(jwt.Confirmation{JWKThumbprint: jkt})
+
+		err = jwt.ConfirmDPoPKey(*proof, *token)
+		if err != nil {
+			t.Error(err)
+		}
+
+		token.SetConfirmation(jwt.Confirmation{JWKThumbprint: "other"})
+
+		err = jwt.ConfirmDPoPKey(*proof, *token)
+		if !errors.Is(err, jwt.ErrInvalidConfirmation) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrInvalidConfirmation, err)
+		}
+	})
+}
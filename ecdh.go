@@ -0,0 +1,246 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+
+	"golang.org/x/crypto/curve25519"
+)
+
+// X25519PublicKey is X25519 public key.
+type X25519PublicKey []byte
+
+// X25519PrivateKey is X25519 private key.
+type X25519PrivateKey []byte
+
+// GenerateX25519Key returns a new random X25519 private key.
+func GenerateX25519Key() (X25519PrivateKey, error) {
+	key, err := generateSecret(curve25519.ScalarSize)
+	if err != nil {
+		return nil, fmt.Errorf("error on generate key: %w", err)
+	}
+
+	return key, nil
+}
+
+// Public returns the public key of the private key.
+func (k X25519PrivateKey) Public() X25519PublicKey {
+	public, _ := curve25519.X25519(k, curve25519.Basepoint)
+
+	return public
+}
+
+func isECDH(alg KeyAlgorithm) bool {
+	return alg == ECDHES || alg == ECDHESA128KW || alg == ECDHESA256KW
+}
+
+// checkECDHKey checks the key is a public (for encryption) or private key of P-256, P-384 or X25519.
+func checkECDHKey(key interface{}, encrypt bool) error {
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		if encrypt {
+			return checkECDHCurve(k.Curve)
+		}
+	case *ecdsa.PrivateKey:
+		if !encrypt {
+			return checkECDHCurve(k.Curve)
+		}
+	case X25519PublicKey:
+		if encrypt && len(k) == curve25519.PointSize {
+			return nil
+		}
+	case X25519PrivateKey:
+		if !encrypt && len(k) == curve25519.ScalarSize {
+			return nil
+		}
+	}
+
+	return ErrInvalidKey
+}
+
+func checkECDHCurve(curve elliptic.Curve) error {
+	if curve != elliptic.P256() && curve != elliptic.P384() {
+		return ErrUnsupportedKeyType
+	}
+
+	return nil
+}
+
+// encryptKeyECDH agrees on a key with an ephemeral key and sets epk header parameter.
+// https://datatracker.ietf.org/doc/html/rfc7518#section-4.6
+func (e Encrypter) encryptKeyECDH(header *EncryptionHeader) (cek, encryptedKey []byte, err error) {
+	var (
+		ephemeral interface{}
+		z         []byte
+	)
+
+	switch recipient := e.key.(type) {
+	case *ecdsa.PublicKey:
+		private, err := ecdsa.GenerateKey(recipient.Curve, rand.Reader)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error on generate ephemeral key: %w", err)
+		}
+
+		ephemeral = &private.PublicKey
+		z = ecdhShared(private, recipient)
+	case X25519PublicKey:
+		private, err := GenerateX25519Key()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		ephemeral = private.Public()
+
+		z, err = curve25519.X25519(private, recipient)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error on agree key: %w", err)
+		}
+	}
+
+	header.EphemeralPublicKey, err = NewJWK(ephemeral)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	derived, err := deriveECDHKey(*header, z)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if e.alg == ECDHES {
+		return derived, nil, nil
+	}
+
+	cek, err = generateContentKey(e.enc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encryptedKey, err = wrapKey(derived, cek)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error on encrypt key: %w", err)
+	}
+
+	return cek, encryptedKey, nil
+}
+
+// decryptKeyECDH agrees on the key with epk header parameter.
+// The ephemeral key must be on the curve of the private key to defend against invalid curve attacks.
+func (d Decrypter) decryptKeyECDH(header *EncryptionHeader, encryptedKey []byte) ([]byte, error) {
+	if header.EphemeralPublicKey == nil || header.EphemeralPublicKey.IsPrivate() {
+		return nil, ErrInvalidToken
+	}
+
+	ephemeral, err := header.EphemeralPublicKey.Key()
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var z []byte
+
+	switch private := d.key.(type) {
+	case *ecdsa.PrivateKey:
+		public, ok := ephemeral.(*ecdsa.PublicKey)
+		if !ok || public.Curve != private.Curve || !private.Curve.IsOnCurve(public.X, public.Y) {
+			return nil, ErrInvalidToken
+		}
+
+		z = ecdhShared(private, public)
+	case X25519PrivateKey:
+		public, ok := ephemeral.(X25519PublicKey)
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+
+		// X25519 fails on low order points, which give an all zero shared secret
+		z, err = curve25519.X25519(private, public)
+		if err != nil {
+			return nil, ErrInvalidToken
+		}
+	}
+
+	derived, err := deriveECDHKey(*header, z)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.alg == ECDHES {
+		if len(encryptedKey) != 0 {
+			return nil, ErrInvalidToken
+		}
+
+		return derived, nil
+	}
+
+	cek, err := unwrapKey(derived, encryptedKey)
+
+	return contentKeyOrRandom(header.Encryption, cek, err)
+}
+
+func ecdhShared(private *ecdsa.PrivateKey, public *ecdsa.PublicKey) []byte {
+	x, _ := private.Curve.ScalarMult(public.X, public.Y, private.D.Bytes())
+
+	return x.FillBytes(make([]byte, curveSize(private.Curve)))
+}
+
+// deriveECDHKey derives the key of the header algorithm from the shared secret.
+// https://datatracker.ietf.org/doc/html/rfc7518#section-4.6.2
+func deriveECDHKey(header EncryptionHeader, z []byte) ([]byte, error) {
+	algID, size := string(header.Algorithm), keyWrapSize(header.Algorithm)
+
+	if header.Algorithm == ECDHES {
+		algID = string(header.Encryption)
+		size, _ = contentKeySize(header.Encryption)
+	}
+
+	apu, err := base64.RawURLEncoding.DecodeString(header.AgreementPartyUInfo)
+	if err != nil {
+		return nil, fmt.Errorf("invalid agreement party u info encoding: %w", err)
+	}
+
+	apv, err := base64.RawURLEncoding.DecodeString(header.AgreementPartyVInfo)
+	if err != nil {
+		return nil, fmt.Errorf("invalid agreement party v info encoding: %w", err)
+	}
+
+	return concatKDF(z, []byte(algID), apu, apv, size), nil
+}
+
+// concatKDF is the Concat KDF of NIST.800-56A with SHA-256.
+// https://datatracker.ietf.org/doc/html/rfc7518#section-4.6.2
+func concatKDF(z, algID, apu, apv []byte, size int) []byte {
+	otherInfo := make([]byte, 0, len(algID)+len(apu)+len(apv))
+
+	for _, info := range [][]byte{algID, apu, apv} {
+		otherInfo = appendUint32(otherInfo, uint32(len(info)))
+		otherInfo = append(otherInfo, info...)
+	}
+
+	otherInfo = appendUint32(otherInfo, uint32(size*bitsPerByte))
+
+	derived := make([]byte, 0, size+sha256.Size)
+
+	for counter := uint32(1); len(derived) < size; counter++ {
+		h := sha256.New()
+		_, _ = h.Write(appendUint32(nil, counter))
+		_, _ = h.Write(z)
+		_, _ = h.Write(otherInfo)
+		derived = h.Sum(derived)
+	}
+
+	return derived[:size]
+}
+
+const uint32Size = 4
+
+func appendUint32(b []byte, v uint32) []byte {
+	buf := make([]byte, uint32Size)
+	binary.BigEndian.PutUint32(buf, v)
+
+	return append(b, buf...)
+}
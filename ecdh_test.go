@@ -0,0 +1,251 @@
+package jwt_test
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+// https://datatracker.ietf.org/doc/html/rfc7518#appendix-C
+const (
+	ecdhAliceJWK = `{"kty":"EC","crv":"P-256",` +
+		`"x":"gI0GAILBdu7T53akrFmMyGcsF3n5dO7MmwNBHKW5SV0",` +
+		`"y":"SLW_xSffzlPWrHEVI30DHM_4egVwt3NQqeUD7nMFpps"}`
+	ecdhBobJWK = `{"kty":"EC","crv":"P-256",` +
+		`"x":"weNJy2HscCSM6AEDTDg04biOvhFhyyWvOHQfeF_PxMQ",` +
+		`"y":"e8lnCO-AlStT-NJVX-crhB7QRYhiix03illJOVAOyck",` +
+		`"d":"VEmDZpDXXK8p8N0Cndsxs924q6nS1RXFASRl6BfUqdw"}`
+	ecdhDerivedKey = "VqqN6vgjbSBcIijNcacQGg"
+)
+
+func TestECDHES(t *testing.T) {
+	t.Parallel()
+
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	x25519, err := jwt.GenerateX25519Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := map[string][2]interface{}{
+		"P-256":  {&p256.PublicKey, p256},
+		"P-384":  {&p384.PublicKey, p384},
+		"X25519": {x25519.Public(), x25519},
+	}
+
+	plaintext := []byte("Live long and prosper.")
+
+	derivedKey, err := base64.RawURLEncoding.DecodeString(ecdhDerivedKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, alg := range []jwt.KeyAlgorithm{jwt.ECDHES, jwt.ECDHESA128KW, jwt.ECDHESA256KW} {
+		for crv, key := range keys {
+			alg, crv, key := alg, crv, key
+
+			t.Run("Encrypt "+string(alg)+" "+crv, func(t *testing.T) {
+				t.Parallel()
+
+				encrypter, err := jwt.NewEncrypter(alg, jwt.A256GCM, key[0], jwt.EncryptWithAgreementPartyInfo([]byte("Alice"), []byte("Bob")))
+				if err != nil {
+					t.Error(err)
+
+					return
+				}
+
+				tokenStr, err := encrypter.Encrypt(plaintext)
+				if err != nil {
+					t.Error(err)
+
+					return
+				}
+
+				decrypter, err := jwt.NewDecrypter(alg, key[1])
+				if err != nil {
+					t.Error(err)
+
+					return
+				}
+
+				decrypted, err := decrypter.Decrypt(tokenStr)
+				if err != nil {
+					t.Error(err)
+				}
+
+				if string(decrypted) != string(plaintext) {
+					t.Errorf("excepted: %q, got: %q", plaintext, decrypted)
+				}
+			})
+		}
+	}
+
+	t.Run("RFC 7518 Appendix C", func(t *testing.T) {
+		t.Parallel()
+
+		var alice, bob jwt.JWK
+
+		if err := json.Unmarshal([]byte(ecdhAliceJWK), &alice); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := json.Unmarshal([]byte(ecdhBobJWK), &bob); err != nil {
+			t.Fatal(err)
+		}
+
+		header := jwt.EncryptionHeader{
+			Algorithm:           jwt.ECDHES,
+			Encryption:          jwt.A128GCM,
+			EphemeralPublicKey:  &alice,
+			AgreementPartyUInfo: "QWxpY2U",
+			AgreementPartyVInfo: "Qm9i",
+		}
+
+		tokenStr := encryptGCM(t, header, derivedKey, nil, plaintext)
+
+		key, err := bob.Key()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decrypter, err := jwt.NewDecrypter(jwt.ECDHES, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decrypted, err := decrypter.Decrypt(tokenStr)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if string(decrypted) != string(plaintext) {
+			t.Errorf("excepted: %q, got: %q", plaintext, decrypted)
+		}
+	})
+
+	t.Run("Reject Invalid Ephemeral Key", func(t *testing.T) {
+		t.Parallel()
+
+		decrypter, err := jwt.NewDecrypter(jwt.ECDHES, p256)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		wrongCurve, _ := jwt.NewJWK(&p384.PublicKey)
+		offCurve, _ := jwt.NewJWK(&p256.PublicKey)
+		offCurve.Y = offCurve.X
+		x25519JWK, _ := jwt.NewJWK(x25519.Public())
+		private, _ := jwt.NewJWK(p256)
+
+		for name, epk := range map[string]*jwt.JWK{
+			"Missing":     nil,
+			"Wrong Curve": wrongCurve,
+			"Off Curve":   offCurve,
+			"Wrong Type":  x25519JWK,
+			"Private":     private,
+		} {
+			header := jwt.EncryptionHeader{Algorithm: jwt.ECDHES, Encryption: jwt.A128GCM, EphemeralPublicKey: epk}
+
+			_, err = decrypter.Decrypt(encryptGCM(t, header, derivedKey, nil, plaintext))
+			if err == nil {
+				t.Errorf("%s: excepted error but got nil", name)
+
+				continue
+			}
+
+			if !errors.Is(err, jwt.ErrInvalidToken) {
+				t.Errorf("%s: excepted error %q, got %q", name, jwt.ErrInvalidToken, err)
+			}
+		}
+	})
+
+	t.Run("Reject Low Order X25519 Ephemeral Key", func(t *testing.T) {
+		t.Parallel()
+
+		decrypter, err := jwt.NewDecrypter(jwt.ECDHES, x25519)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		epk, _ := jwt.NewJWK(jwt.X25519PublicKey(make([]byte, 32)))
+		header := jwt.EncryptionHeader{Algorithm: jwt.ECDHES, Encryption: jwt.A128GCM, EphemeralPublicKey: epk}
+
+		_, err = decrypter.Decrypt(encryptGCM(t, header, derivedKey, nil, plaintext))
+		if !errors.Is(err, jwt.ErrInvalidToken) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrInvalidToken, err)
+		}
+	})
+
+	t.Run("Unsupported Key", func(t *testing.T) {
+		t.Parallel()
+
+		p521, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = jwt.NewEncrypter(jwt.ECDHES, jwt.A128GCM, &p521.PublicKey)
+		if !errors.Is(err, jwt.ErrUnsupportedKeyType) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrUnsupportedKeyType, err)
+		}
+
+		_, err = jwt.NewEncrypter(jwt.ECDHES, jwt.A128GCM, p256)
+		if !errors.Is(err, jwt.ErrInvalidKey) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrInvalidKey, err)
+		}
+	})
+}
+
+// encryptGCM returns compact json web encryption of the plaintext encrypted with the content encryption key.
+func encryptGCM(t *testing.T, header jwt.EncryptionHeader, cek, encryptedKey, plaintext []byte) string {
+	t.Helper()
+
+	headerBytes, err := json.Marshal(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encodedHeader := base64.RawURLEncoding.EncodeToString(headerBytes)
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	iv := make([]byte, aead.NonceSize())
+	sealed := aead.Seal(nil, iv, plaintext, []byte(encodedHeader))
+	tagStart := len(sealed) - aead.Overhead()
+
+	return strings.Join([]string{
+		encodedHeader,
+		base64.RawURLEncoding.EncodeToString(encryptedKey),
+		base64.RawURLEncoding.EncodeToString(iv),
+		base64.RawURLEncoding.EncodeToString(sealed[:tagStart]),
+		base64.RawURLEncoding.EncodeToString(sealed[tagStart:]),
+	}, ".")
+}
@@ -0,0 +1,158 @@
+package jwt
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// Default parameter names of bearer tokens.
+// https://datatracker.ietf.org/doc/html/rfc6750#section-2.2
+const (
+	AccessTokenParameter = "access_token"
+)
+
+var (
+	ErrTokenNotFound  = errors.New("token not found")
+	ErrMultipleTokens = errors.New("token found in more than one place")
+)
+
+// Extractor extracts token string from request.
+// It returns ErrTokenNotFound when the request carries no token
+// and ErrMultipleTokens when the request carries more than one.
+type Extractor interface {
+	Extract(r *http.Request) (string, error)
+}
+
+// ExtractorFunc adapts a function to Extractor.
+type ExtractorFunc func(r *http.Request) (string, error)
+
+// Extract calls f(r).
+func (f ExtractorFunc) Extract(r *http.Request) (string, error) {
+	return f(r)
+}
+
+// HeaderExtractor extracts bearer token of authorization request header field.
+// https://datatracker.ietf.org/doc/html/rfc6750#section-2.1
+func HeaderExtractor() Extractor {
+	return ExtractorFunc(func(r *http.Request) (string, error) {
+		tokens := make([]string, 0)
+
+		for _, authorization := range r.Header.Values("Authorization") {
+			if t, ok := bearerToken(authorization); ok {
+				tokens = append(tokens, t)
+			}
+		}
+
+		return singleToken(tokens)
+	})
+}
+
+// CookieExtractor extracts token of the cookie.
+func CookieExtractor(name string) Extractor {
+	return ExtractorFunc(func(r *http.Request) (string, error) {
+		tokens := make([]string, 0)
+
+		for _, cookie := range r.Cookies() {
+			if cookie.Name == name && cookie.Value != "" {
+				tokens = append(tokens, cookie.Value)
+			}
+		}
+
+		return singleToken(tokens)
+	})
+}
+
+// QueryExtractor extracts token of the uri query parameter, e.g. AccessTokenParameter.
+// https://datatracker.ietf.org/doc/html/rfc6750#section-2.3
+func QueryExtractor(param string) Extractor {
+	return ExtractorFunc(func(r *http.Request) (string, error) {
+		return singleToken(nonEmpty(r.URL.Query()[param]))
+	})
+}
+
+// FormExtractor extracts token of the form-encoded body parameter, e.g. AccessTokenParameter.
+// Only bodies of application/x-www-form-urlencoded content type and methods other than GET are read.
+// https://datatracker.ietf.org/doc/html/rfc6750#section-2.2
+func FormExtractor(param string) Extractor {
+	return ExtractorFunc(func(r *http.Request) (string, error) {
+		if r.Method == http.MethodGet || r.Body == nil {
+			return "", ErrTokenNotFound
+		}
+
+		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if contentType != "application/x-www-form-urlencoded" {
+			return "", ErrTokenNotFound
+		}
+
+		err := r.ParseForm()
+		if err != nil {
+			return "", ErrTokenNotFound
+		}
+
+		return singleToken(nonEmpty(r.PostForm[param]))
+	})
+}
+
+// ChainExtractor runs every extractor and returns the token that exactly one of them finds.
+// Tokens found by more than one extractor are rejected with ErrMultipleTokens.
+// https://datatracker.ietf.org/doc/html/rfc6750#section-2
+func ChainExtractor(extractors ...Extractor) Extractor {
+	return ExtractorFunc(func(r *http.Request) (string, error) {
+		tokens := make([]string, 0)
+
+		for i := range extractors {
+			t, err := extractors[i].Extract(r)
+			if errors.Is(err, ErrTokenNotFound) {
+				continue
+			}
+
+			if err != nil {
+				return "", err
+			}
+
+			tokens = append(tokens, t)
+		}
+
+		return singleToken(tokens)
+	})
+}
+
+func singleToken(tokens []string) (string, error) {
+	switch len(tokens) {
+	case 0:
+		return "", ErrTokenNotFound
+	case 1:
+		return tokens[0], nil
+	default:
+		return "", ErrMultipleTokens
+	}
+}
+
+func nonEmpty(values []string) []string {
+	res := make([]string, 0, len(values))
+
+	for i := range values {
+		if values[i] != "" {
+			res = append(res, values[i])
+		}
+	}
+
+	return res
+}
+
+// bearerToken returns the token of authorization header value.
+func bearerToken(authorization string) (string, bool) {
+	if len(authorization) <= len(bearerScheme)+1 || authorization[len(bearerScheme)] != ' ' {
+		return "", false
+	}
+
+	if !strings.EqualFold(authorization[:len(bearerScheme)], bearerScheme) {
+		return "", false
+	}
+
+	t := strings.TrimSpace(authorization[len(bearerScheme)+1:])
+
+	return t, t != ""
+}
@@ -1,3 +1,5 @@
 module github.com/nasermirzaei89/jwt
 
 go 1.15
+
+require golang.org/x/crypto v0.8.0
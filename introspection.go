@@ -0,0 +1,175 @@
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TypeTokenIntrospection is typ header parameter of signed token introspection responses.
+// https://datatracker.ietf.org/doc/html/rfc9701#section-5
+const TypeTokenIntrospection = "token-introspection+jwt"
+
+// ContentTypeTokenIntrospection is the media type of signed token introspection responses,
+// e.g. for Accept header of introspection requests.
+// https://datatracker.ietf.org/doc/html/rfc9701#section-4
+const ContentTypeTokenIntrospection = "application/" + TypeTokenIntrospection
+
+// ClaimTokenIntrospection is token_introspection claim.
+const ClaimTokenIntrospection = "token_introspection"
+
+var introspectionResponseClaims = []string{ClaimIssuer, ClaimAudience, ClaimIssuedAt, ClaimTokenIntrospection}
+
+// IntrospectionResponse is token introspection response.
+// https://datatracker.ietf.org/doc/html/rfc7662#section-2.2
+type IntrospectionResponse struct {
+	Active         bool          `json:"active"`
+	Scope          string        `json:"scope,omitempty"`
+	ClientID       string        `json:"client_id,omitempty"`
+	Username       string        `json:"username,omitempty"`
+	TokenType      string        `json:"token_type,omitempty"`
+	ExpirationTime int64         `json:"exp,omitempty"`
+	IssuedAt       int64         `json:"iat,omitempty"`
+	NotBefore      int64         `json:"nbf,omitempty"`
+	Subject        string        `json:"sub,omitempty"`
+	Audience       []string      `json:"aud,omitempty"`
+	Issuer         string        `json:"iss,omitempty"`
+	JWTID          string        `json:"jti,omitempty"`
+	Confirmation   *Confirmation `json:"cnf,omitempty"`
+}
+
+// UnmarshalJSON decodes introspection response with aud of a string or an array of strings.
+func (r *IntrospectionResponse) UnmarshalJSON(b []byte) error {
+	type response IntrospectionResponse
+
+	var v struct {
+		response
+		Audience interface{} `json:"aud,omitempty"`
+	}
+
+	err := json.Unmarshal(b, &v)
+	if err != nil {
+		return err
+	}
+
+	*r = IntrospectionResponse(v.response)
+
+	token := Token{payload: map[string]interface{}{ClaimAudience: v.Audience}}
+
+	if v.Audience != nil {
+		r.Audience, err = token.GetAudience()
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", ClaimAudience, err)
+		}
+	}
+
+	return nil
+}
+
+// SignIntrospectionResponse signs introspection response of the issuer for the resource server audience.
+// Responses of inactive tokens are signed with active member only.
+// https://datatracker.ietf.org/doc/html/rfc9701#section-5
+func (s Signer) SignIntrospectionResponse(issuer, audience string, response IntrospectionResponse) (string, error) {
+	if !response.Active {
+		response = IntrospectionResponse{}
+	}
+
+	jti, err := newJWTID()
+	if err != nil {
+		return "", err
+	}
+
+	token := New(s.alg)
+	token.header.Type = TypeTokenIntrospection
+	token.SetIssuer(issuer)
+	token.SetAudience(audience)
+	token.SetIssuedAt(time.Now())
+	token.SetJWTID(jti)
+	token.Set(ClaimTokenIntrospection, response)
+
+	return s.Sign(*token)
+}
+
+// IntrospectionResponseValidator validates signed introspection responses of an issuer for a resource server.
+type IntrospectionResponseValidator struct {
+	issuer   string
+	audience string
+}
+
+// NewIntrospectionResponseValidator returns introspection response validator of the issuer and
+// the resource server audience.
+func NewIntrospectionResponseValidator(issuer, audience string) *IntrospectionResponseValidator {
+	return &IntrospectionResponseValidator{
+		issuer:   issuer,
+		audience: audience,
+	}
+}
+
+// Verify signed introspection response string with the verifier, validate its claims and return
+// its token_introspection claim. Tokens of other types than token-introspection+jwt are rejected
+// with ErrUnsupportedTokenType.
+func (v IntrospectionResponseValidator) Verify(t string, verifier TokenVerifier) (*IntrospectionResponse, error) {
+	token, err := verifier.VerifyType(t, TypeTokenIntrospection)
+	if err != nil {
+		return nil, err
+	}
+
+	err = v.Validate(*token)
+	if err != nil {
+		return nil, err
+	}
+
+	return token.GetTokenIntrospection()
+}
+
+// Validate the claims of verified introspection response.
+func (v IntrospectionResponseValidator) Validate(token Token) error {
+	if !isType(token.header.Type, TypeTokenIntrospection) {
+		return ErrUnsupportedTokenType
+	}
+
+	err := requireClaims(token, introspectionResponseClaims...)
+	if err != nil {
+		return err
+	}
+
+	if iss, err := token.GetIssuer(); err != nil || iss != v.issuer {
+		return ErrInvalidIssuer
+	}
+
+	if aud, err := token.GetAudience(); err != nil || !containsAll(aud, []string{v.audience}) {
+		return ErrInvalidAudience
+	}
+
+	return token.Validate()
+}
+
+// GetTokenIntrospection returns token_introspection claim.
+func (t Token) GetTokenIntrospection() (*IntrospectionResponse, error) {
+	value, exists := t.payload[ClaimTokenIntrospection]
+	if !exists {
+		return nil, ErrClaimNotFound
+	}
+
+	if response, ok := value.(IntrospectionResponse); ok {
+		return &response, nil
+	}
+
+	if _, ok := value.(map[string]interface{}); !ok {
+		return nil, ErrInvalidClaimType
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("error on marshal token introspection: %w", err)
+	}
+
+	var response IntrospectionResponse
+
+	err = json.Unmarshal(b, &response)
+	if err != nil {
+		return nil, ErrInvalidClaimType
+	}
+
+	return &response, nil
+}
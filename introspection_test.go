@@ -0,0 +1,150 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestIntrospectionResponse(t *testing.T) {
+	t.Parallel()
+
+	const (
+		issuer   = "https://as.example.com"
+		audience = "https://rs.example.com"
+	)
+
+	signer, err := jwt.NewSigner(jwt.RS256, []byte(private))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := jwt.NewVerifier(jwt.RS256, []byte(public))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validator := jwt.NewIntrospectionResponseValidator(issuer, audience)
+
+	t.Run("Active", func(t *testing.T) {
+		t.Parallel()
+
+		tokenStr, err := signer.SignIntrospectionResponse(issuer, audience, jwt.IntrospectionResponse{
+			Active:         true,
+			Scope:          "read write",
+			ClientID:       "s6BhdRkqt3",
+			Subject:        "Z5O3upPC88QrAjx00dis",
+			Audience:       []string{audience},
+			ExpirationTime: 1419356238,
+			Confirmation:   &jwt.Confirmation{X509Thumbprint: "bwcK0esc3ACC3DB2Y5_lESsXE8o9ltc05O89jdN-dg2"},
+		})
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		res, err := validator.Verify(tokenStr, *verifier)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if !res.Active || res.Scope != "read write" || res.ExpirationTime != 1419356238 || res.Audience[0] != audience {
+			t.Errorf("unexcepted response %+v", res)
+		}
+
+		if res.Confirmation == nil || res.Confirmation.X509Thumbprint != "bwcK0esc3ACC3DB2Y5_lESsXE8o9ltc05O89jdN-dg2" {
+			t.Errorf("unexcepted confirmation %+v", res.Confirmation)
+		}
+	})
+
+	t.Run("Inactive", func(t *testing.T) {
+		t.Parallel()
+
+		tokenStr, err := signer.SignIntrospectionResponse(issuer, audience, jwt.IntrospectionResponse{Active: false, Subject: "Z5O3upPC88QrAjx00dis"})
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		res, err := validator.Verify(tokenStr, *verifier)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if res.Active || res.Subject != "" {
+			t.Errorf("excepted inactive response without members, got %+v", res)
+		}
+	})
+
+	t.Run("Audience String", func(t *testing.T) {
+		t.Parallel()
+
+		var res jwt.IntrospectionResponse
+
+		err := json.Unmarshal([]byte(`{"active":true,"aud":"`+audience+`"}`), &res)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if len(res.Audience) != 1 || res.Audience[0] != audience {
+			t.Errorf("excepted: %q, got: %q", []string{audience}, res.Audience)
+		}
+	})
+
+	accessToken, err := jwt.NewAccessToken(jwt.RS256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accessToken.SetIssuer(issuer)
+	accessToken.SetAudience(audience)
+	accessToken.Set(jwt.ClaimTokenIntrospection, map[string]interface{}{"active": true})
+
+	accessTokenStr, err := signer.Sign(*accessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherIssuer, err := signer.SignIntrospectionResponse("https://other.example.com", audience, jwt.IntrospectionResponse{Active: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherAudience, err := signer.SignIntrospectionResponse(issuer, "https://other.example.com", jwt.IntrospectionResponse{Active: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{"Type", accessTokenStr, jwt.ErrUnsupportedTokenType},
+		{"Issuer", otherIssuer, jwt.ErrInvalidIssuer},
+		{"Audience", otherAudience, jwt.ErrInvalidAudience},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := validator.Verify(tt.token, *verifier)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("excepted error %q, got %q", tt.err, err)
+			}
+		})
+	}
+}
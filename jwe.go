@@ -0,0 +1,351 @@
+package jwt
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeyAlgorithm is json web encryption key management algorithm.
+type KeyAlgorithm string
+
+// Key Management Algorithms.
+const (
+	Direct     KeyAlgorithm = "dir"
+	A128KW     KeyAlgorithm = "A128KW"
+	A256KW     KeyAlgorithm = "A256KW"
+	RSAOAEP256 KeyAlgorithm = "RSA-OAEP-256"
+
+	ECDHES       KeyAlgorithm = "ECDH-ES"
+	ECDHESA128KW KeyAlgorithm = "ECDH-ES+A128KW"
+	ECDHESA256KW KeyAlgorithm = "ECDH-ES+A256KW"
+
+	PBES2HS256A128KW KeyAlgorithm = "PBES2-HS256+A128KW"
+	PBES2HS512A256KW KeyAlgorithm = "PBES2-HS512+A256KW"
+)
+
+// ContentEncryption is json web encryption content encryption algorithm.
+type ContentEncryption string
+
+// Content Encryption Algorithms.
+const (
+	A128GCM      ContentEncryption = "A128GCM"
+	A256GCM      ContentEncryption = "A256GCM"
+	A128CBCHS256 ContentEncryption = "A128CBC-HS256"
+)
+
+const encryptedTokenParts = 5
+
+var (
+	ErrUnsupportedEncryption = errors.New("unsupported content encryption algorithm")
+	ErrDecryptionFailed      = errors.New("token decryption failed")
+)
+
+// EncryptionHeader is json web encryption header.
+type EncryptionHeader struct {
+	Algorithm   KeyAlgorithm      `json:"alg"`
+	Encryption  ContentEncryption `json:"enc"`
+	Type        string            `json:"typ,omitempty"`
+	ContentType string            `json:"cty,omitempty"`
+	KeyID       string            `json:"kid,omitempty"`
+
+	Compression CompressionAlgorithm `json:"zip,omitempty"`
+
+	// https://datatracker.ietf.org/doc/html/rfc7518#section-4.6.1
+	EphemeralPublicKey  *JWK   `json:"epk,omitempty"`
+	AgreementPartyUInfo string `json:"apu,omitempty"`
+	AgreementPartyVInfo string `json:"apv,omitempty"`
+
+	// https://datatracker.ietf.org/doc/html/rfc7518#section-4.8.1
+	PBES2Salt  string `json:"p2s,omitempty"`
+	PBES2Count int    `json:"p2c,omitempty"`
+}
+
+// Encrypter encrypts payloads to compact json web encryption.
+type Encrypter struct {
+	alg        KeyAlgorithm
+	enc        ContentEncryption
+	key        interface{}
+	header     EncryptionHeader
+	pbes2Count int
+}
+
+// EncrypterOption configures encrypter.
+type EncrypterOption func(*Encrypter)
+
+// EncryptWithKeyID sets kid header parameter.
+func EncryptWithKeyID(kid string) EncrypterOption {
+	return func(e *Encrypter) {
+		e.header.KeyID = kid
+	}
+}
+
+// EncryptWithContentType sets cty header parameter.
+func EncryptWithContentType(cty string) EncrypterOption {
+	return func(e *Encrypter) {
+		e.header.ContentType = cty
+	}
+}
+
+// EncryptWithType sets typ header parameter.
+func EncryptWithType(typ string) EncrypterOption {
+	return func(e *Encrypter) {
+		e.header.Type = typ
+	}
+}
+
+// EncryptWithAgreementPartyInfo sets apu and apv header parameters of ECDH-ES key agreement.
+func EncryptWithAgreementPartyInfo(apu, apv []byte) EncrypterOption {
+	return func(e *Encrypter) {
+		e.header.AgreementPartyUInfo = base64.RawURLEncoding.EncodeToString(apu)
+		e.header.AgreementPartyVInfo = base64.RawURLEncoding.EncodeToString(apv)
+	}
+}
+
+// NewEncrypter returns an encrypter of the key management and content encryption algorithms.
+// The key is a []byte for dir and AES key wrap algorithms, the password for PBES2 algorithms, *rsa.PublicKey for RSA-OAEP-256
+// and *ecdsa.PublicKey of P-256 or P-384 or X25519PublicKey for ECDH-ES algorithms.
+func NewEncrypter(alg KeyAlgorithm, enc ContentEncryption, key interface{}, opts ...EncrypterOption) (*Encrypter, error) {
+	cekSize, err := contentKeySize(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	e := Encrypter{
+		alg:        alg,
+		enc:        enc,
+		key:        key,
+		pbes2Count: DefaultPBES2Count,
+	}
+
+	for i := range opts {
+		opts[i](&e)
+	}
+
+	e.header.Algorithm = alg
+	e.header.Encryption = enc
+
+	err = checkEncryptionKey(alg, cekSize, key, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if isPBES2(alg) && e.pbes2Count < MinPBES2Count {
+		return nil, ErrWeakKey
+	}
+
+	return &e, nil
+}
+
+// Encrypt the plaintext and return compact json web encryption.
+// https://datatracker.ietf.org/doc/html/rfc7516#section-5.1
+func (e Encrypter) Encrypt(plaintext []byte) (string, error) {
+	return e.encrypt(e.header, plaintext)
+}
+
+func (e Encrypter) encrypt(header EncryptionHeader, plaintext []byte) (string, error) {
+	if header.Compression == Deflate {
+		compressed, err := compress(plaintext)
+		if err != nil {
+			return "", err
+		}
+
+		plaintext = compressed
+	}
+
+	cek, encryptedKey, err := e.encryptKey(&header)
+	if err != nil {
+		return "", err
+	}
+
+	headerBytes, err := json.Marshal(header)
+	if err != nil {
+		return "", fmt.Errorf("error on marshal header: %w", err)
+	}
+
+	encodedHeader := base64.RawURLEncoding.EncodeToString(headerBytes)
+
+	iv, ciphertext, tag, err := encryptContent(e.enc, cek, plaintext, []byte(encodedHeader))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join([]string{
+		encodedHeader,
+		base64.RawURLEncoding.EncodeToString(encryptedKey),
+		base64.RawURLEncoding.EncodeToString(iv),
+		base64.RawURLEncoding.EncodeToString(ciphertext),
+		base64.RawURLEncoding.EncodeToString(tag),
+	}, "."), nil
+}
+
+// Decrypter decrypts compact json web encryption.
+type Decrypter struct {
+	alg                 KeyAlgorithm
+	key                 interface{}
+	maxPBES2Count       int
+	maxDecompressedSize int64
+}
+
+// DecrypterOption configures decrypter.
+type DecrypterOption func(*Decrypter)
+
+// NewDecrypter returns a decrypter of the key management algorithm.
+// The key is a []byte for dir and AES key wrap algorithms, the password for PBES2 algorithms, *rsa.PrivateKey for RSA-OAEP-256
+// and *ecdsa.PrivateKey of P-256 or P-384 or X25519PrivateKey for ECDH-ES algorithms.
+func NewDecrypter(alg KeyAlgorithm, key interface{}, opts ...DecrypterOption) (*Decrypter, error) {
+	d := Decrypter{
+		alg:                 alg,
+		key:                 key,
+		maxPBES2Count:       DefaultMaxPBES2Count,
+		maxDecompressedSize: DefaultMaxDecompressedSize,
+	}
+
+	for i := range opts {
+		opts[i](&d)
+	}
+
+	err := checkEncryptionKey(alg, 0, key, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// Decrypt compact json web encryption and return the plaintext.
+// Tokens encrypted with any other key management algorithm than the decrypter's are rejected.
+func (d Decrypter) Decrypt(t string) ([]byte, error) {
+	_, plaintext, err := d.decrypt(t)
+
+	return plaintext, err
+}
+
+// https://datatracker.ietf.org/doc/html/rfc7516#section-5.2
+func (d Decrypter) decrypt(t string) (*EncryptionHeader, []byte, error) {
+	arr := strings.Split(t, ".")
+	if len(arr) != encryptedTokenParts {
+		return nil, nil, ErrInvalidToken
+	}
+
+	header, err := decodeEncryptionHeader(arr[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if header.Algorithm != d.alg {
+		return nil, nil, ErrUnsupportedAlgorithm
+	}
+
+	parts := make([][]byte, encryptedTokenParts)
+
+	for i := 1; i < encryptedTokenParts; i++ {
+		parts[i], err = base64.RawURLEncoding.DecodeString(arr[i])
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid token encoding: %w", err)
+		}
+	}
+
+	cek, err := d.decryptKey(header, parts[1])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	plaintext, err := decryptContent(header.Encryption, cek, parts[2], parts[3], parts[4], []byte(arr[0]))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if header.Compression == Deflate {
+		plaintext, err = decompress(plaintext, d.maxDecompressedSize)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return header, plaintext, nil
+}
+
+func decodeEncryptionHeader(encodedHeader string) (*EncryptionHeader, error) {
+	var header EncryptionHeader
+
+	b, err := base64.RawURLEncoding.DecodeString(encodedHeader)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token header encoding: %w", err)
+	}
+
+	err = json.Unmarshal(b, &header)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token header: %w", err)
+	}
+
+	// https://datatracker.ietf.org/doc/html/rfc7516#section-4.1.13
+	var params map[string]interface{}
+
+	_ = json.Unmarshal(b, &params)
+	if _, ok := params["crit"]; ok {
+		return nil, ErrInvalidToken
+	}
+
+	_, err = contentKeySize(header.Encryption)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkCompression(header.Compression)
+	if err != nil {
+		return nil, err
+	}
+
+	return &header, nil
+}
+
+// checkEncryptionKey checks the key is of the type used by the key management algorithm.
+// For encryption cekSize is the content encryption key size, which dir keys must match.
+func checkEncryptionKey(alg KeyAlgorithm, cekSize int, key interface{}, encrypt bool) error {
+	switch alg {
+	case Direct, A128KW, A256KW:
+		secret, ok := key.([]byte)
+		if !ok {
+			return ErrInvalidKey
+		}
+
+		size := cekSize
+		if alg != Direct {
+			size = keyWrapSize(alg)
+		}
+
+		if size != 0 && len(secret) != size {
+			return ErrInvalidKey
+		}
+	case RSAOAEP256:
+		var public *rsa.PublicKey
+
+		if encrypt {
+			public, _ = key.(*rsa.PublicKey)
+		} else if private, ok := key.(*rsa.PrivateKey); ok {
+			public = &private.PublicKey
+		}
+
+		if public == nil {
+			return ErrInvalidKey
+		}
+
+		if public.N.BitLen() < MinRSAKeyBits {
+			return ErrWeakKey
+		}
+	case ECDHES, ECDHESA128KW, ECDHESA256KW:
+		return checkECDHKey(key, encrypt)
+	case PBES2HS256A128KW, PBES2HS512A256KW:
+		if password, ok := key.([]byte); !ok || len(password) == 0 {
+			return ErrInvalidKey
+		}
+	default:
+		return ErrUnsupportedAlgorithm
+	}
+
+	return nil
+}
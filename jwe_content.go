@@ -0,0 +1,193 @@
+package jwt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	aes128KeySize = 16
+	aes256KeySize = 32
+	gcmTagSize    = 16
+	cbcHMACSize   = 16
+	lengthSize    = 8
+)
+
+func contentKeySize(enc ContentEncryption) (int, error) {
+	switch enc {
+	case A128GCM:
+		return aes128KeySize, nil
+	case A256GCM, A128CBCHS256:
+		return aes256KeySize, nil
+	default:
+		return 0, ErrUnsupportedEncryption
+	}
+}
+
+func generateContentKey(enc ContentEncryption) ([]byte, error) {
+	size, err := contentKeySize(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	cek, err := generateSecret(size)
+	if err != nil {
+		return nil, fmt.Errorf("error on generate content encryption key: %w", err)
+	}
+
+	return cek, nil
+}
+
+// https://datatracker.ietf.org/doc/html/rfc7516#section-5.1
+func encryptContent(enc ContentEncryption, cek, plaintext, aad []byte) (iv, ciphertext, tag []byte, err error) {
+	switch enc {
+	case A128GCM, A256GCM:
+		return encryptGCM(cek, plaintext, aad)
+	case A128CBCHS256:
+		return encryptCBCHMAC(cek, plaintext, aad)
+	default:
+		return nil, nil, nil, ErrUnsupportedEncryption
+	}
+}
+
+// https://datatracker.ietf.org/doc/html/rfc7516#section-5.2
+func decryptContent(enc ContentEncryption, cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	size, err := contentKeySize(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cek) != size {
+		return nil, ErrDecryptionFailed
+	}
+
+	switch enc {
+	case A128GCM, A256GCM:
+		return decryptGCM(cek, iv, ciphertext, tag, aad)
+	default:
+		return decryptCBCHMAC(cek, iv, ciphertext, tag, aad)
+	}
+}
+
+// https://datatracker.ietf.org/doc/html/rfc7518#section-5.3
+func encryptGCM(cek, plaintext, aad []byte) (iv, ciphertext, tag []byte, err error) {
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error on create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error on create cipher: %w", err)
+	}
+
+	iv, err = generateSecret(aead.NonceSize())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error on generate initialization vector: %w", err)
+	}
+
+	sealed := aead.Seal(nil, iv, plaintext, aad)
+
+	return iv, sealed[:len(sealed)-gcmTagSize], sealed[len(sealed)-gcmTagSize:], nil
+}
+
+func decryptGCM(cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, fmt.Errorf("error on create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error on create cipher: %w", err)
+	}
+
+	if len(iv) != aead.NonceSize() || len(tag) != gcmTagSize {
+		return nil, ErrDecryptionFailed
+	}
+
+	plaintext, err := aead.Open(nil, iv, append(append([]byte{}, ciphertext...), tag...), aad)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+
+	return plaintext, nil
+}
+
+// https://datatracker.ietf.org/doc/html/rfc7518#section-5.2.2
+func encryptCBCHMAC(cek, plaintext, aad []byte) (iv, ciphertext, tag []byte, err error) {
+	macKey, encKey := cek[:len(cek)/2], cek[len(cek)/2:]
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error on create cipher: %w", err)
+	}
+
+	iv, err = generateSecret(aes.BlockSize)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error on generate initialization vector: %w", err)
+	}
+
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	ciphertext = make([]byte, len(plaintext)+padding)
+	copy(ciphertext, plaintext)
+
+	for i := len(plaintext); i < len(ciphertext); i++ {
+		ciphertext[i] = byte(padding)
+	}
+
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+
+	return iv, ciphertext, cbcHMACTag(macKey, aad, iv, ciphertext), nil
+}
+
+func decryptCBCHMAC(cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	macKey, encKey := cek[:len(cek)/2], cek[len(cek)/2:]
+
+	if !hmac.Equal(tag, cbcHMACTag(macKey, aad, iv, ciphertext)) {
+		return nil, ErrDecryptionFailed
+	}
+
+	if len(iv) != aes.BlockSize || len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, ErrDecryptionFailed
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, fmt.Errorf("error on create cipher: %w", err)
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return nil, ErrDecryptionFailed
+	}
+
+	for _, b := range plaintext[len(plaintext)-padding:] {
+		if subtle.ConstantTimeByteEq(b, byte(padding)) != 1 {
+			return nil, ErrDecryptionFailed
+		}
+	}
+
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+func cbcHMACTag(macKey, aad, iv, ciphertext []byte) []byte {
+	al := make([]byte, lengthSize)
+	binary.BigEndian.PutUint64(al, uint64(len(aad))*bitsPerByte)
+
+	mac := hmac.New(sha256.New, macKey)
+	_, _ = mac.Write(aad)
+	_, _ = mac.Write(iv)
+	_, _ = mac.Write(ciphertext)
+	_, _ = mac.Write(al)
+
+	return mac.Sum(nil)[:cbcHMACSize]
+}
@@ -0,0 +1,189 @@
+package jwt
+
+import (
+	"crypto/aes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	keyWrapBlockSize = 8
+	keyWrapRounds    = 6
+)
+
+// keyWrapDefaultIV is the default initial value of AES key wrap.
+// https://datatracker.ietf.org/doc/html/rfc3394#section-2.2.3.1
+var keyWrapDefaultIV = []byte{0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6}
+
+func keyWrapSize(alg KeyAlgorithm) int {
+	switch alg {
+	case A128KW, ECDHESA128KW, PBES2HS256A128KW:
+		return aes128KeySize
+	case A256KW, ECDHESA256KW, PBES2HS512A256KW:
+		return aes256KeySize
+	default:
+		return 0
+	}
+}
+
+// encryptKey returns the content encryption key and the encrypted key of the token.
+// https://datatracker.ietf.org/doc/html/rfc7516#section-5.1
+func (e Encrypter) encryptKey(header *EncryptionHeader) (cek, encryptedKey []byte, err error) {
+	if e.alg == Direct {
+		return e.key.([]byte), nil, nil
+	}
+
+	if isECDH(e.alg) {
+		return e.encryptKeyECDH(header)
+	}
+
+	if isPBES2(e.alg) {
+		return e.encryptKeyPBES2(header)
+	}
+
+	cek, err = generateContentKey(e.enc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch e.alg {
+	case A128KW, A256KW:
+		encryptedKey, err = wrapKey(e.key.([]byte), cek)
+	case RSAOAEP256:
+		encryptedKey, err = rsa.EncryptOAEP(sha256.New(), rand.Reader, e.key.(*rsa.PublicKey), cek, nil)
+	default:
+		return nil, nil, ErrUnsupportedAlgorithm
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("error on encrypt key: %w", err)
+	}
+
+	return cek, encryptedKey, nil
+}
+
+// decryptKey returns the content encryption key of the token.
+// https://datatracker.ietf.org/doc/html/rfc7516#section-5.2
+func (d Decrypter) decryptKey(header *EncryptionHeader, encryptedKey []byte) ([]byte, error) {
+	switch d.alg {
+	case Direct:
+		if len(encryptedKey) != 0 {
+			return nil, ErrInvalidToken
+		}
+
+		return d.key.([]byte), nil
+	case A128KW, A256KW:
+		cek, err := unwrapKey(d.key.([]byte), encryptedKey)
+
+		return contentKeyOrRandom(header.Encryption, cek, err)
+	case RSAOAEP256:
+		cek, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, d.key.(*rsa.PrivateKey), encryptedKey, nil)
+
+		return contentKeyOrRandom(header.Encryption, cek, err)
+	case ECDHES, ECDHESA128KW, ECDHESA256KW:
+		return d.decryptKeyECDH(header, encryptedKey)
+	case PBES2HS256A128KW, PBES2HS512A256KW:
+		return d.decryptKeyPBES2(header, encryptedKey)
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+}
+
+// contentKeyOrRandom returns the decrypted content encryption key, or a random key of the content encryption
+// when the key decryption failed or the key is of another size, so that the token is rejected by content decryption
+// like one with a modified tag and key decryption failures cannot be told apart by timing.
+// https://datatracker.ietf.org/doc/html/rfc7516#section-11.5
+func contentKeyOrRandom(enc ContentEncryption, cek []byte, err error) ([]byte, error) {
+	random, genErr := generateContentKey(enc)
+	if genErr != nil {
+		return nil, genErr
+	}
+
+	if err != nil || len(cek) != len(random) {
+		return random, nil
+	}
+
+	return cek, nil
+}
+
+// wrapKey wraps the key with AES key wrap.
+// https://datatracker.ietf.org/doc/html/rfc3394#section-2.2.1
+func wrapKey(kek, key []byte) ([]byte, error) {
+	if len(key) == 0 || len(key)%keyWrapBlockSize != 0 {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(key) / keyWrapBlockSize
+
+	r := make([]byte, len(key))
+	copy(r, key)
+
+	a := make([]byte, keyWrapBlockSize)
+	copy(a, keyWrapDefaultIV)
+
+	b := make([]byte, aes.BlockSize)
+
+	for j := 0; j < keyWrapRounds; j++ {
+		for i := 0; i < n; i++ {
+			copy(b, a)
+			copy(b[keyWrapBlockSize:], r[i*keyWrapBlockSize:])
+			block.Encrypt(b, b)
+
+			t := uint64(n*j + i + 1)
+			binary.BigEndian.PutUint64(a, binary.BigEndian.Uint64(b[:keyWrapBlockSize])^t)
+			copy(r[i*keyWrapBlockSize:], b[keyWrapBlockSize:])
+		}
+	}
+
+	return append(a, r...), nil
+}
+
+// unwrapKey unwraps the key with AES key wrap.
+// https://datatracker.ietf.org/doc/html/rfc3394#section-2.2.2
+func unwrapKey(kek, wrapped []byte) ([]byte, error) {
+	if len(wrapped) < 3*keyWrapBlockSize || len(wrapped)%keyWrapBlockSize != 0 {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(wrapped)/keyWrapBlockSize - 1
+
+	a := make([]byte, keyWrapBlockSize)
+	copy(a, wrapped[:keyWrapBlockSize])
+
+	r := make([]byte, len(wrapped)-keyWrapBlockSize)
+	copy(r, wrapped[keyWrapBlockSize:])
+
+	b := make([]byte, aes.BlockSize)
+
+	for j := keyWrapRounds - 1; j >= 0; j-- {
+		for i := n - 1; i >= 0; i-- {
+			t := uint64(n*j + i + 1)
+			binary.BigEndian.PutUint64(b, binary.BigEndian.Uint64(a)^t)
+			copy(b[keyWrapBlockSize:], r[i*keyWrapBlockSize:])
+			block.Decrypt(b, b)
+
+			copy(a, b[:keyWrapBlockSize])
+			copy(r[i*keyWrapBlockSize:], b[keyWrapBlockSize:])
+		}
+	}
+
+	if subtle.ConstantTimeCompare(a, keyWrapDefaultIV) != 1 {
+		return nil, ErrInvalidKey
+	}
+
+	return r, nil
+}
@@ -0,0 +1,403 @@
+package jwt_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestEncrypter(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, jwt.MinRSAKeyBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext := []byte("Live long and prosper.")
+
+	for _, enc := range []jwt.ContentEncryption{jwt.A128GCM, jwt.A256GCM, jwt.A128CBCHS256} {
+		enc := enc
+
+		keys := map[jwt.KeyAlgorithm][2]interface{}{
+			jwt.A128KW:     {strongSecret[:16], strongSecret[:16]},
+			jwt.A256KW:     {strongSecret[:32], strongSecret[:32]},
+			jwt.RSAOAEP256: {&rsaKey.PublicKey, rsaKey},
+		}
+
+		if enc == jwt.A128GCM {
+			keys[jwt.Direct] = [2]interface{}{strongSecret[:16], strongSecret[:16]}
+		} else {
+			keys[jwt.Direct] = [2]interface{}{strongSecret[:32], strongSecret[:32]}
+		}
+
+		for alg, key := range keys {
+			alg, key := alg, key
+
+			t.Run("Encrypt "+string(alg)+" "+string(enc), func(t *testing.T) {
+				t.Parallel()
+
+				encrypter, err := jwt.NewEncrypter(alg, enc, key[0])
+				if err != nil {
+					t.Error(err)
+
+					return
+				}
+
+				tokenStr, err := encrypter.Encrypt(plaintext)
+				if err != nil {
+					t.Error(err)
+
+					return
+				}
+
+				if parts := len(strings.Split(tokenStr, ".")); parts != 5 {
+					t.Errorf("excepted: %d, got: %d", 5, parts)
+				}
+
+				decrypter, err := jwt.NewDecrypter(alg, key[1])
+				if err != nil {
+					t.Error(err)
+
+					return
+				}
+
+				decrypted, err := decrypter.Decrypt(tokenStr)
+				if err != nil {
+					t.Error(err)
+				}
+
+				if string(decrypted) != string(plaintext) {
+					t.Errorf("excepted: %q, got: %q", plaintext, decrypted)
+				}
+			})
+		}
+	}
+
+	t.Run("Invalid direct key size", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := jwt.NewEncrypter(jwt.Direct, jwt.A256GCM, strongSecret[:16])
+		if !errors.Is(err, jwt.ErrInvalidKey) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Unsupported content encryption", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := jwt.NewEncrypter(jwt.A128KW, "A192GCM", strongSecret[:16])
+		if !errors.Is(err, jwt.ErrUnsupportedEncryption) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Weak RSA key", func(t *testing.T) {
+		t.Parallel()
+
+		weak, err := rsa.GenerateKey(rand.Reader, 1024)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_, err = jwt.NewEncrypter(jwt.RSAOAEP256, jwt.A256GCM, &weak.PublicKey)
+		if !errors.Is(err, jwt.ErrWeakKey) {
+			t.Error(err)
+		}
+	})
+}
+
+// rfc7520Plaintext is the plaintext of RFC 7520 section 5 examples.
+// https://datatracker.ietf.org/doc/html/rfc7520#section-5
+const rfc7520Plaintext = "You can trust us to stick with you through thick and thin\u2013to the bitter end. " +
+	"And you can trust us to keep any secret of yours\u2013closer than you keep it yourself. " +
+	"But you cannot trust us to let you face trouble alone, and go off without a word. " +
+	"We are your friends, Frodo."
+
+func TestDecrypter(t *testing.T) {
+	t.Parallel()
+
+	// https://datatracker.ietf.org/doc/html/rfc7516#appendix-A.3
+	key, err := base64.RawURLEncoding.DecodeString("GawgguFyGrWKav7AX4VKUg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenStr := "eyJhbGciOiJBMTI4S1ciLCJlbmMiOiJBMTI4Q0JDLUhTMjU2In0." +
+		"6KB707dM9YTIgHtLvtgWQ8mKwboJW3of9locizkDTHzBC2IlrT1oOQ." +
+		"AxY8DCtDaGlsbGljb3RoZQ." +
+		"KDlTtXchhZTGufMYmOYGS4HffxPSUrfmqCHXaI9wOGY." +
+		"U0m_YmjN04DJvceFICbCVQ"
+
+	decrypter, err := jwt.NewDecrypter(jwt.A128KW, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Decrypt A128KW A128CBC-HS256", func(t *testing.T) {
+		t.Parallel()
+
+		excepted := "Live long and prosper."
+
+		plaintext, err := decrypter.Decrypt(tokenStr)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if string(plaintext) != excepted {
+			t.Errorf("excepted: %q, got: %q", excepted, plaintext)
+		}
+	})
+
+	// https://datatracker.ietf.org/doc/html/rfc7520#section-5
+	rfc7520Tests := []struct {
+		name  string
+		alg   jwt.KeyAlgorithm
+		key   string
+		token string
+	}{
+		{
+			// https://datatracker.ietf.org/doc/html/rfc7520#section-5.6
+			name: "RFC 7520 5.6 dir A128GCM",
+			alg:  jwt.Direct,
+			key:  "XctOhJAkA-pD9Lh7ZgW_2A",
+			token: "eyJhbGciOiJkaXIiLCJraWQiOiI3N2M3ZTJiOC02ZTEzLTQ1Y2YtODY3Mi02MTdiNWI0NTI0M2EiLCJlbmMiOiJBMTI4R0NNIn0" +
+				"." +
+				".refa467QzzKx6QAB" +
+				".JW_i_f52hww_ELQPGaYyeAB6HYGcR559l9TYnSovc23XJoBcW29rHP8yZOZG7YhLpT1bjFuvZPjQS-m0IFtVcXkZXdH_lr_Frd" +
+				"Yt9HRUYkshtrMmIUAyGmUnd9zMDB2n0cRDIHAzFVeJUDxkUwVAE7_YGRPdcqMyiBoCO-FBdE-Nceb4h3-FtBP-c_BIwCPTjb9o0S" +
+				"bdcdREEMJMyZBH8ySWMVi1gPD9yxi-aQpGbSv_F9N4IZAxscj5g-NJsUPbjk29-s7LJAGb15wEBtXphVCgyy53CoIKLHHeJHXex45U" +
+				"z9aKZSRSInZI-wjsY0yu3cT4_aQ3i1o-tiE-F8Ios61EKgyIQ4CWao8PFMj8TTnp" +
+				".vbb32Xvllea2OtmHAdccRQ",
+		},
+		{
+			// https://datatracker.ietf.org/doc/html/rfc7520#section-5.8
+			name: "RFC 7520 5.8 A128KW A128GCM",
+			alg:  jwt.A128KW,
+			key:  "GZy6sIZ6wl9NJOKB-jnmVQ",
+			token: "eyJhbGciOiJBMTI4S1ciLCJraWQiOiI4MWIyMDk2NS04MzMyLTQzZDktYTQ2OC04MjE2MGFkOTFhYzgiLCJlbmMiOiJBMTI4R0NNIn0" +
+				".CBI6oDw8MydIx1IBntf_lQcw2MmJKIQx" +
+				".Qx0pmsDa8KnJc9Jo" +
+				".AwliP-KmWgsZ37BvzCefNen6VTbRK3QMA4TkvRkH0tP1bTdhtFJgJxeVmJkLD61A1hnWGetdg11c9ADsnWgL56NyxwSYjU1ZEH" +
+				"cGkd3EkU0vjHi9gTlb90qSYFfeF0LwkcTtjbYKCsiNJQkcIp1yeM03OmuiYSoYJVSpf7ej6zaYcMv3WwdxDFl8REwOhNImk2Xld2" +
+				"JXq6BR53TSFkyT7PwVLuq-1GwtGHlQeg7gDT6xW0JqHDPn_H-puQsmthc9Zg0ojmJfqqFvETUxLAF-KjcBTS5dNy6egwkYtOt8EI" +
+				"HK-oEsKYtZRaa8Z7MOZ7UGxGIMvEmxrGCPeJa14slv2-gaqK0kEThkaSqdYw0FkQZF" +
+				".ER7MWJZ1FBI_NKvn7Zb1Lw",
+		},
+	}
+
+	for i := range rfc7520Tests {
+		tt := rfc7520Tests[i]
+
+		t.Run("Decrypt "+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			key, err := base64.RawURLEncoding.DecodeString(tt.key)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			decrypter, err := jwt.NewDecrypter(tt.alg, key)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			plaintext, err := decrypter.Decrypt(tt.token)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			if string(plaintext) != rfc7520Plaintext {
+				t.Errorf("excepted: %q, got: %q", rfc7520Plaintext, plaintext)
+			}
+		})
+	}
+
+	// The key of RFC 3394 section 4.3 wrapped with A256KW decrypts content encrypted with it.
+	// https://datatracker.ietf.org/doc/html/rfc3394#section-4.3
+	t.Run("Decrypt RFC 3394 4.3 A256KW A128GCM", func(t *testing.T) {
+		t.Parallel()
+
+		kek, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")
+		cek, _ := hex.DecodeString("00112233445566778899aabbccddeeff")
+		encryptedKey, _ := hex.DecodeString("64e8c3f9ce0f5ba263e9777905818a2a93c8191e7d6e8ae7")
+
+		decrypter, err := jwt.NewDecrypter(jwt.A256KW, kek)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		header := jwt.EncryptionHeader{Algorithm: jwt.A256KW, Encryption: jwt.A128GCM}
+
+		plaintext, err := decrypter.Decrypt(encryptGCM(t, header, cek, encryptedKey, []byte(rfc7520Plaintext)))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if string(plaintext) != rfc7520Plaintext {
+			t.Errorf("excepted: %q, got: %q", rfc7520Plaintext, plaintext)
+		}
+	})
+
+	// RFC 7520 section 5.2 encrypts its key with RSA-OAEP, which is not supported,
+	// so its content encryption key is encrypted here with RSA-OAEP-256 of the standard library.
+	// https://datatracker.ietf.org/doc/html/rfc7520#section-5.2
+	t.Run("Decrypt RFC 7520 5.2 adapted to RSA-OAEP-256 A256GCM", func(t *testing.T) {
+		t.Parallel()
+
+		rsaKey, err := rsa.GenerateKey(rand.Reader, jwt.MinRSAKeyBits)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		cek, _ := base64.RawURLEncoding.DecodeString("mYMfsggkTAm0TbvtlFh2hyoXnbEzJQjMxmgLN3d8xXA")
+
+		encryptedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, &rsaKey.PublicKey, cek, nil)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		decrypter, err := jwt.NewDecrypter(jwt.RSAOAEP256, rsaKey)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		header := jwt.EncryptionHeader{Algorithm: jwt.RSAOAEP256, Encryption: jwt.A256GCM}
+
+		plaintext, err := decrypter.Decrypt(encryptGCM(t, header, cek, encryptedKey, []byte(rfc7520Plaintext)))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if string(plaintext) != rfc7520Plaintext {
+			t.Errorf("excepted: %q, got: %q", rfc7520Plaintext, plaintext)
+		}
+	})
+
+	t.Run("Decrypt modified tag", func(t *testing.T) {
+		t.Parallel()
+
+		plaintext, err := decrypter.Decrypt(tokenStr[:len(tokenStr)-1] + "A")
+		if !errors.Is(err, jwt.ErrDecryptionFailed) {
+			t.Error(err)
+		}
+
+		if plaintext != nil {
+			t.Errorf("excepted nil but got %q", plaintext)
+		}
+	})
+
+	t.Run("Decrypt with wrong key", func(t *testing.T) {
+		t.Parallel()
+
+		wrong, err := jwt.NewDecrypter(jwt.A128KW, strongSecret[:16])
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_, err = wrong.Decrypt(tokenStr)
+		if !errors.Is(err, jwt.ErrDecryptionFailed) {
+			t.Error(err)
+		}
+	})
+
+	// Failed key decryption continues with a random content encryption key and fails on the content tag.
+	// https://datatracker.ietf.org/doc/html/rfc7516#section-11.5
+	t.Run("Decrypt with wrong RSA key", func(t *testing.T) {
+		t.Parallel()
+
+		rsaKey, err := rsa.GenerateKey(rand.Reader, jwt.MinRSAKeyBits)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		otherKey, err := rsa.GenerateKey(rand.Reader, jwt.MinRSAKeyBits)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		encrypter, err := jwt.NewEncrypter(jwt.RSAOAEP256, jwt.A256GCM, &rsaKey.PublicKey)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		tokenStr, err := encrypter.Encrypt([]byte("secret"))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		wrong, err := jwt.NewDecrypter(jwt.RSAOAEP256, otherKey)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_, err = wrong.Decrypt(tokenStr)
+		if !errors.Is(err, jwt.ErrDecryptionFailed) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrDecryptionFailed, err)
+		}
+	})
+
+	t.Run("Decrypt other algorithm", func(t *testing.T) {
+		t.Parallel()
+
+		other, err := jwt.NewDecrypter(jwt.A256KW, strongSecret[:32])
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_, err = other.Decrypt(tokenStr)
+		if !errors.Is(err, jwt.ErrUnsupportedAlgorithm) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Decrypt invalid token", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := decrypter.Decrypt("invalid")
+		if !errors.Is(err, jwt.ErrInvalidToken) {
+			t.Error(err)
+		}
+	})
+}
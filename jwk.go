@@ -0,0 +1,394 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+
+	"golang.org/x/crypto/curve25519"
+)
+
+// Key Types.
+const (
+	KeyTypeRSA = "RSA"
+	KeyTypeEC  = "EC"
+	KeyTypeOKP = "OKP"
+	KeyTypeOct = "oct"
+)
+
+// Public Key Uses.
+const (
+	KeyUseSignature  = "sig"
+	KeyUseEncryption = "enc"
+)
+
+// Curves.
+const (
+	CurveP256    = "P-256"
+	CurveP384    = "P-384"
+	CurveP521    = "P-521"
+	CurveEd25519 = "Ed25519"
+	CurveX25519  = "X25519"
+)
+
+const (
+	rsaPrimes          = 2
+	maxRSAExponentBits = 31
+	bitsPerByte        = 8
+)
+
+// JWK is json web key.
+type JWK struct {
+	KeyType   string   `json:"kty"`
+	Use       string   `json:"use,omitempty"`
+	KeyOps    []string `json:"key_ops,omitempty"`
+	Algorithm string   `json:"alg,omitempty"`
+	KeyID     string   `json:"kid,omitempty"`
+	Curve     string   `json:"crv,omitempty"`
+	X         string   `json:"x,omitempty"`
+	Y         string   `json:"y,omitempty"`
+	N         string   `json:"n,omitempty"`
+	E         string   `json:"e,omitempty"`
+	D         string   `json:"d,omitempty"`
+	P         string   `json:"p,omitempty"`
+	Q         string   `json:"q,omitempty"`
+	DP        string   `json:"dp,omitempty"`
+	DQ        string   `json:"dq,omitempty"`
+	QI        string   `json:"qi,omitempty"`
+	K         string   `json:"k,omitempty"`
+}
+
+// NewJWK returns json web key of a symmetric secret, rsa, ecdsa, ed25519 or x25519 key.
+func NewJWK(key interface{}) (*JWK, error) {
+	switch k := key.(type) {
+	case []byte:
+		return &JWK{KeyType: KeyTypeOct, K: encodeBytes(k)}, nil
+	case *rsa.PublicKey:
+		return &JWK{KeyType: KeyTypeRSA, N: encodeInt(k.N), E: encodeInt(big.NewInt(int64(k.E)))}, nil
+	case *rsa.PrivateKey:
+		if len(k.Primes) != rsaPrimes {
+			return nil, ErrUnsupportedKeyType
+		}
+
+		p, q := k.Primes[0], k.Primes[1]
+		one := big.NewInt(1)
+
+		// CRT values are computed here rather than with Precompute, which would modify the caller's key.
+		jwk, _ := NewJWK(&k.PublicKey)
+		jwk.D = encodeInt(k.D)
+		jwk.P = encodeInt(p)
+		jwk.Q = encodeInt(q)
+		jwk.DP = encodeInt(new(big.Int).Mod(k.D, new(big.Int).Sub(p, one)))
+		jwk.DQ = encodeInt(new(big.Int).Mod(k.D, new(big.Int).Sub(q, one)))
+		jwk.QI = encodeInt(new(big.Int).ModInverse(q, p))
+
+		return jwk, nil
+	case *ecdsa.PublicKey:
+		crv, err := curveName(k.Curve)
+		if err != nil {
+			return nil, err
+		}
+
+		size := curveSize(k.Curve)
+
+		return &JWK{KeyType: KeyTypeEC, Curve: crv, X: encodeFixed(k.X, size), Y: encodeFixed(k.Y, size)}, nil
+	case *ecdsa.PrivateKey:
+		jwk, err := NewJWK(&k.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+
+		jwk.D = encodeFixed(k.D, curveSize(k.Curve))
+
+		return jwk, nil
+	case ed25519.PublicKey:
+		return &JWK{KeyType: KeyTypeOKP, Curve: CurveEd25519, X: encodeBytes(k)}, nil
+	case ed25519.PrivateKey:
+		jwk, _ := NewJWK(k.Public())
+		jwk.D = encodeBytes(k.Seed())
+
+		return jwk, nil
+	case X25519PublicKey:
+		return &JWK{KeyType: KeyTypeOKP, Curve: CurveX25519, X: encodeBytes(k)}, nil
+	case X25519PrivateKey:
+		jwk, _ := NewJWK(k.Public())
+		jwk.D = encodeBytes(k)
+
+		return jwk, nil
+	default:
+		return nil, ErrUnsupportedKeyType
+	}
+}
+
+// IsPrivate reports whether the json web key holds private or secret key material.
+func (k JWK) IsPrivate() bool {
+	return k.D != "" || k.K != ""
+}
+
+// Public returns the json web key without its private key material.
+func (k JWK) Public() JWK {
+	k.D, k.P, k.Q, k.DP, k.DQ, k.QI, k.K = "", "", "", "", "", "", ""
+	k.KeyOps = nil
+
+	return k
+}
+
+// Thumbprint returns the base64url encoded json web key thumbprint with the hash, e.g. crypto.SHA256.
+// https://datatracker.ietf.org/doc/html/rfc7638
+func (k JWK) Thumbprint(hash crypto.Hash) (string, error) {
+	var members string
+
+	// required members in lexicographic order
+	// https://datatracker.ietf.org/doc/html/rfc7638#section-3.2
+	switch k.KeyType {
+	case KeyTypeEC:
+		members = fmt.Sprintf(`{"crv":%q,"kty":%q,"x":%q,"y":%q}`, k.Curve, k.KeyType, k.X, k.Y)
+	case KeyTypeRSA:
+		members = fmt.Sprintf(`{"e":%q,"kty":%q,"n":%q}`, k.E, k.KeyType, k.N)
+	case KeyTypeOKP:
+		members = fmt.Sprintf(`{"crv":%q,"kty":%q,"x":%q}`, k.Curve, k.KeyType, k.X)
+	case KeyTypeOct:
+		members = fmt.Sprintf(`{"k":%q,"kty":%q}`, k.K, k.KeyType)
+	default:
+		return "", ErrUnsupportedKeyType
+	}
+
+	if !hash.Available() {
+		return "", ErrUnsupportedAlgorithm
+	}
+
+	return encodeBytes(digest(hash, []byte(members))), nil
+}
+
+// Key returns the key held by the json web key.
+// It is a []byte for symmetric keys, otherwise one of the rsa, ecdsa or ed25519 public or private key types.
+func (k JWK) Key() (interface{}, error) {
+	switch k.KeyType {
+	case KeyTypeOct:
+		return k.octKey()
+	case KeyTypeRSA:
+		return k.rsaKey()
+	case KeyTypeEC:
+		return k.ecKey()
+	case KeyTypeOKP:
+		return k.okpKey()
+	default:
+		return nil, ErrUnsupportedKeyType
+	}
+}
+
+func (k JWK) octKey() ([]byte, error) {
+	secret, err := decodeBytes(k.K)
+	if err != nil || len(secret) == 0 {
+		return nil, ErrInvalidKey
+	}
+
+	return secret, nil
+}
+
+func (k JWK) rsaKey() (interface{}, error) {
+	n, err := decodeInt(k.N)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := decodeInt(k.E)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.BitLen() > maxRSAExponentBits {
+		return nil, ErrInvalidKey
+	}
+
+	public := rsa.PublicKey{N: n, E: int(e.Int64())}
+
+	if k.D == "" {
+		return &public, nil
+	}
+
+	d, err := decodeInt(k.D)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := decodeInt(k.P)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := decodeInt(k.Q)
+	if err != nil {
+		return nil, err
+	}
+
+	private := &rsa.PrivateKey{PublicKey: public, D: d, Primes: []*big.Int{p, q}}
+
+	err = private.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("error on validate private key: %w", err)
+	}
+
+	private.Precompute()
+
+	return private, nil
+}
+
+func (k JWK) ecKey() (interface{}, error) {
+	curve, err := curveByName(k.Curve)
+	if err != nil {
+		return nil, err
+	}
+
+	x, err := decodeInt(k.X)
+	if err != nil {
+		return nil, err
+	}
+
+	y, err := decodeInt(k.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	if !curve.IsOnCurve(x, y) {
+		return nil, ErrInvalidKey
+	}
+
+	public := ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+
+	if k.D == "" {
+		return &public, nil
+	}
+
+	d, err := decodeInt(k.D)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.Sign() <= 0 || d.Cmp(curve.Params().N) >= 0 {
+		return nil, ErrInvalidKey
+	}
+
+	if px, py := curve.ScalarBaseMult(d.Bytes()); px.Cmp(x) != 0 || py.Cmp(y) != 0 {
+		return nil, ErrInvalidKey
+	}
+
+	return &ecdsa.PrivateKey{PublicKey: public, D: d}, nil
+}
+
+func (k JWK) okpKey() (interface{}, error) {
+	if k.Curve == CurveX25519 {
+		return k.x25519Key()
+	}
+
+	if k.Curve != CurveEd25519 {
+		return nil, ErrUnsupportedKeyType
+	}
+
+	x, err := decodeBytes(k.X)
+	if err != nil || len(x) != ed25519.PublicKeySize {
+		return nil, ErrInvalidKey
+	}
+
+	if k.D == "" {
+		return ed25519.PublicKey(x), nil
+	}
+
+	d, err := decodeBytes(k.D)
+	if err != nil || len(d) != ed25519.SeedSize {
+		return nil, ErrInvalidKey
+	}
+
+	private := ed25519.NewKeyFromSeed(d)
+	if !private.Public().(ed25519.PublicKey).Equal(ed25519.PublicKey(x)) {
+		return nil, ErrInvalidKey
+	}
+
+	return private, nil
+}
+
+func (k JWK) x25519Key() (interface{}, error) {
+	x, err := decodeBytes(k.X)
+	if err != nil || len(x) != curve25519.PointSize {
+		return nil, ErrInvalidKey
+	}
+
+	if k.D == "" {
+		return X25519PublicKey(x), nil
+	}
+
+	d, err := decodeBytes(k.D)
+	if err != nil || len(d) != curve25519.ScalarSize {
+		return nil, ErrInvalidKey
+	}
+
+	private := X25519PrivateKey(d)
+	if !bytes.Equal(private.Public(), x) {
+		return nil, ErrInvalidKey
+	}
+
+	return private, nil
+}
+
+func curveName(curve elliptic.Curve) (string, error) {
+	switch curve {
+	case elliptic.P256():
+		return CurveP256, nil
+	case elliptic.P384():
+		return CurveP384, nil
+	case elliptic.P521():
+		return CurveP521, nil
+	default:
+		return "", ErrUnsupportedKeyType
+	}
+}
+
+func curveByName(name string) (elliptic.Curve, error) {
+	switch name {
+	case CurveP256:
+		return elliptic.P256(), nil
+	case CurveP384:
+		return elliptic.P384(), nil
+	case CurveP521:
+		return elliptic.P521(), nil
+	default:
+		return nil, ErrUnsupportedKeyType
+	}
+}
+
+func curveSize(curve elliptic.Curve) int {
+	return (curve.Params().BitSize + bitsPerByte - 1) / bitsPerByte
+}
+
+func encodeBytes(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func encodeInt(i *big.Int) string {
+	return encodeBytes(i.Bytes())
+}
+
+func encodeFixed(i *big.Int, size int) string {
+	b := make([]byte, size)
+
+	return encodeBytes(i.FillBytes(b))
+}
+
+func decodeBytes(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	b, err := decodeBytes(s)
+	if err != nil || len(b) == 0 {
+		return nil, ErrInvalidKey
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,264 @@
+package jwt
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// cty header parameters of encrypted json web keys and key sets.
+// https://datatracker.ietf.org/doc/html/rfc7517#section-8.5
+const (
+	contentTypeJWK    = "jwk+json"
+	contentTypeJWKSet = "jwk-set+json"
+)
+
+// ErrKeyNotFound is returned when no json web key matches.
+var ErrKeyNotFound = errors.New("key not found")
+
+// JWKSet is json web key set.
+// https://datatracker.ietf.org/doc/html/rfc7517#section-5
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// LookupKeyID returns the json web key with the key id.
+func (s JWKSet) LookupKeyID(kid string) (*JWK, error) {
+	for i := range s.Keys {
+		if s.Keys[i].KeyID == kid {
+			return &s.Keys[i], nil
+		}
+	}
+
+	return nil, ErrKeyNotFound
+}
+
+// Public returns the json web key set without private key material.
+// Symmetric keys are left out as they have no public part.
+func (s JWKSet) Public() JWKSet {
+	public := JWKSet{Keys: make([]JWK, 0, len(s.Keys))}
+
+	for i := range s.Keys {
+		if s.Keys[i].KeyType == KeyTypeOct {
+			continue
+		}
+
+		public.Keys = append(public.Keys, s.Keys[i].Public())
+	}
+
+	return public
+}
+
+// Encrypt the json web key set, e.g. with PBES2 passphrase or a key wrapping key, to keep key material at rest encrypted.
+// https://datatracker.ietf.org/doc/html/rfc7517#section-9.2
+func (s JWKSet) Encrypt(encrypter Encrypter) (string, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return "", fmt.Errorf("error on marshal key set: %w", err)
+	}
+
+	header := encrypter.header
+	header.ContentType = contentTypeJWKSet
+
+	return encrypter.encrypt(header, b)
+}
+
+// DecryptJWKSet decrypts json web key set encrypted by JWKSet.Encrypt.
+func DecryptJWKSet(t string, decrypter Decrypter) (*JWKSet, error) {
+	header, plaintext, err := decrypter.decrypt(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.ToLower(header.ContentType) != contentTypeJWKSet {
+		return nil, ErrInvalidToken
+	}
+
+	var s JWKSet
+
+	err = json.Unmarshal(plaintext, &s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key set: %w", err)
+	}
+
+	for i := range s.Keys {
+		_, err = s.Keys[i].Key()
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", s.Keys[i].KeyID, err)
+		}
+	}
+
+	return &s, nil
+}
+
+// Encrypt the json web key, e.g. to send a symmetric proof-of-possession key in jwe confirmation member.
+// https://datatracker.ietf.org/doc/html/rfc7517#section-7
+func (k JWK) Encrypt(encrypter Encrypter) (string, error) {
+	b, err := json.Marshal(k)
+	if err != nil {
+		return "", fmt.Errorf("error on marshal key: %w", err)
+	}
+
+	header := encrypter.header
+	header.ContentType = contentTypeJWK
+
+	return encrypter.encrypt(header, b)
+}
+
+// DecryptJWK decrypts json web key encrypted by JWK.Encrypt.
+func DecryptJWK(t string, decrypter Decrypter) (*JWK, error) {
+	header, plaintext, err := decrypter.decrypt(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.ToLower(header.ContentType) != contentTypeJWK {
+		return nil, ErrInvalidToken
+	}
+
+	var k JWK
+
+	err = json.Unmarshal(plaintext, &k)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key: %w", err)
+	}
+
+	_, err = k.Key()
+	if err != nil {
+		return nil, fmt.Errorf("invalid key: %w", err)
+	}
+
+	return &k, nil
+}
+
+// NewJWKSigner returns a signer of the json web key algorithm with its secret or private key.
+// Signed tokens have the key id of the json web key unless SignWithKeyID is set.
+func NewJWKSigner(jwk JWK, opts ...SignerOption) (*Signer, error) {
+	opts = append([]SignerOption{SignWithKeyID(jwk.KeyID)}, opts...)
+	alg := Algorithm(jwk.Algorithm)
+	if !isSupported(alg) {
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	key, err := jwk.Key()
+	if err != nil {
+		return nil, err
+	}
+
+	if isHMAC(alg) {
+		return newSigner(alg, key, opts)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+
+	return NewCryptoSigner(alg, signer, opts...)
+}
+
+// NewJWKVerifier returns a verifier of the json web key algorithm with its secret or public key.
+func NewJWKVerifier(jwk JWK, opts ...VerifierOption) (*Verifier, error) {
+	return newJWKVerifier(jwk, Algorithm(jwk.Algorithm), opts)
+}
+
+func newJWKVerifier(jwk JWK, alg Algorithm, opts []VerifierOption) (*Verifier, error) {
+	if !isSupported(alg) {
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	if isHMAC(alg) {
+		key, err := jwk.Key()
+		if err != nil {
+			return nil, err
+		}
+
+		return newVerifier(alg, key, opts)
+	}
+
+	key, err := jwk.Public().Key()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPublicKeyVerifier(alg, key, opts...)
+}
+
+// KeySetVerifier verifies tokens signed by any key of a json web key set with any of the allowed algorithms.
+type KeySetVerifier struct {
+	set  JWKSet
+	algs []Algorithm
+	opts []VerifierOption
+}
+
+// NewKeySetVerifier returns a verifier of the key set that accepts only the algorithms.
+func NewKeySetVerifier(set JWKSet, algs []Algorithm, opts ...VerifierOption) *KeySetVerifier {
+	return &KeySetVerifier{
+		set:  set,
+		algs: algs,
+		opts: opts,
+	}
+}
+
+// Verify token string.
+func (v KeySetVerifier) Verify(t string) error {
+	_, err := v.VerifyType(t, typeJWT)
+
+	return err
+}
+
+// VerifyType verifies token string of any of the media types and returns the parsed token.
+// The key is chosen by kid header parameter; tokens without it are tried with every key usable for the algorithm.
+func (v KeySetVerifier) VerifyType(t string, types ...string) (*Token, error) {
+	token, err := Parse(t)
+	if err != nil {
+		return nil, err
+	}
+
+	alg := token.header.Algorithm
+
+	if !containsAll(algorithmNames(v.algs), []string{string(alg)}) {
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	err = ErrKeyNotFound
+
+	for i := range v.set.Keys {
+		jwk := v.set.Keys[i]
+
+		if token.header.KeyID != "" && jwk.KeyID != token.header.KeyID {
+			continue
+		}
+
+		// https://datatracker.ietf.org/doc/html/rfc7517#section-4.2
+		if (jwk.Algorithm != "" && jwk.Algorithm != string(alg)) || (jwk.Use != "" && jwk.Use != KeyUseSignature) {
+			continue
+		}
+
+		verifier, verifierErr := newJWKVerifier(jwk, alg, v.opts)
+		if verifierErr != nil {
+			continue
+		}
+
+		verified, verifyErr := verifier.VerifyType(t, types...)
+		if !errors.Is(verifyErr, ErrInvalidTokenSignature) {
+			return verified, verifyErr
+		}
+
+		err = verifyErr
+	}
+
+	return nil, err
+}
+
+func algorithmNames(algs []Algorithm) []string {
+	names := make([]string, len(algs))
+
+	for i := range algs {
+		names[i] = string(algs[i])
+	}
+
+	return names
+}
@@ -0,0 +1,197 @@
+package jwt_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestJWKSet(t *testing.T) {
+	t.Parallel()
+
+	var set jwt.JWKSet
+
+	for _, alg := range []jwt.Algorithm{jwt.HS256, jwt.RS256, jwt.ES256, jwt.EdDSA} {
+		key, err := jwt.GenerateKey(alg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		jwk, err := key.JWK()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		jwk.KeyID = string(alg)
+		set.Keys = append(set.Keys, *jwk)
+	}
+
+	password := []byte("correct horse battery staple")
+
+	t.Run("Encrypt With Passphrase", func(t *testing.T) {
+		t.Parallel()
+
+		encrypter, err := jwt.NewEncrypter(jwt.PBES2HS512A256KW, jwt.A256GCM, password, jwt.EncryptWithPBES2Count(jwt.MinPBES2Count))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		tokenStr, err := set.Encrypt(*encrypter)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		decrypter, err := jwt.NewDecrypter(jwt.PBES2HS512A256KW, password)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		loaded, err := jwt.DecryptJWKSet(tokenStr, *decrypter)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if len(loaded.Keys) != len(set.Keys) {
+			t.Errorf("excepted: %d, got: %d", len(set.Keys), len(loaded.Keys))
+
+			return
+		}
+
+		public := loaded.Public()
+
+		for _, jwk := range loaded.Keys {
+			signer, err := jwt.NewJWKSigner(jwk)
+			if err != nil {
+				t.Errorf("%s: %s", jwk.KeyID, err)
+
+				continue
+			}
+
+			tokenStr, err := signer.Sign(*jwt.New(signer.Algorithm()))
+			if err != nil {
+				t.Errorf("%s: %s", jwk.KeyID, err)
+
+				continue
+			}
+
+			verificationKey := &jwk
+
+			if jwk.KeyType != jwt.KeyTypeOct {
+				verificationKey, err = public.LookupKeyID(jwk.KeyID)
+				if err != nil {
+					t.Errorf("%s: %s", jwk.KeyID, err)
+
+					continue
+				}
+
+				if verificationKey.IsPrivate() {
+					t.Errorf("%s: excepted public key", jwk.KeyID)
+				}
+			}
+
+			verifier, err := jwt.NewJWKVerifier(*verificationKey)
+			if err != nil {
+				t.Errorf("%s: %s", jwk.KeyID, err)
+
+				continue
+			}
+
+			err = verifier.Verify(tokenStr)
+			if err != nil {
+				t.Errorf("%s: %s", jwk.KeyID, err)
+			}
+		}
+	})
+
+	t.Run("Encrypt With Wrapping Key", func(t *testing.T) {
+		t.Parallel()
+
+		encrypter, err := jwt.NewEncrypter(jwt.A256KW, jwt.A256GCM, strongSecret[:32])
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		tokenStr, err := set.Encrypt(*encrypter)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		decrypter, err := jwt.NewDecrypter(jwt.A256KW, strongSecret[:32])
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		loaded, err := jwt.DecryptJWKSet(tokenStr, *decrypter)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if _, err := loaded.LookupKeyID(string(jwt.ES256)); err != nil {
+			t.Error(err)
+		}
+
+		_, err = loaded.LookupKeyID("unknown")
+		if !errors.Is(err, jwt.ErrKeyNotFound) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrKeyNotFound, err)
+		}
+	})
+
+	t.Run("Not Key Set", func(t *testing.T) {
+		t.Parallel()
+
+		encrypter, err := jwt.NewEncrypter(jwt.A256KW, jwt.A256GCM, strongSecret[:32])
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		tokenStr, err := encrypter.Encrypt([]byte(`{"keys":[]}`))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		decrypter, err := jwt.NewDecrypter(jwt.A256KW, strongSecret[:32])
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_, err = jwt.DecryptJWKSet(tokenStr, *decrypter)
+		if !errors.Is(err, jwt.ErrInvalidToken) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrInvalidToken, err)
+		}
+	})
+
+	t.Run("Signer Without Algorithm", func(t *testing.T) {
+		t.Parallel()
+
+		jwk := set.Keys[1]
+		jwk.Algorithm = ""
+
+		_, err := jwt.NewJWKSigner(jwk)
+		if !errors.Is(err, jwt.ErrUnsupportedAlgorithm) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrUnsupportedAlgorithm, err)
+		}
+	})
+}
@@ -0,0 +1,295 @@
+package jwt_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestJWK(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Unsupported key", func(t *testing.T) {
+		t.Parallel()
+
+		jwk, err := jwt.NewJWK("foo")
+		if !errors.Is(err, jwt.ErrUnsupportedKeyType) {
+			t.Error(err)
+		}
+
+		if jwk != nil {
+			t.Errorf("excepted nil but got '%T'", jwk)
+		}
+	})
+
+	t.Run("Symmetric key", func(t *testing.T) {
+		t.Parallel()
+
+		jwk, err := jwt.NewJWK(secret)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		excepted := `{"kty":"oct","k":"c2VjcmV0X2tleQ"}`
+
+		b, err := json.Marshal(jwk)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if string(b) != excepted {
+			t.Errorf("excepted: %q, got: %q", excepted, string(b))
+		}
+
+		key, err := jwk.Key()
+		if err != nil {
+			t.Error(err)
+		}
+
+		if string(key.([]byte)) != string(secret) {
+			t.Errorf("excepted: %q, got: %q", secret, key)
+		}
+	})
+
+	t.Run("RSA private key round trip", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := jwt.GenerateKey(jwt.RS256)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		jwk, err := key.JWK()
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		var decoded jwt.JWK
+
+		b, _ := json.Marshal(jwk)
+
+		err = json.Unmarshal(b, &decoded)
+		if err != nil {
+			t.Error(err)
+		}
+
+		private, err := decoded.Key()
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if !key.Raw().(*rsa.PrivateKey).Equal(private) {
+			t.Error("excepted equal private keys")
+		}
+
+		public, err := decoded.Public().Key()
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if !key.Public().(*rsa.PublicKey).Equal(public) {
+			t.Error("excepted equal public keys")
+		}
+	})
+
+	t.Run("RSA private key is not modified", func(t *testing.T) {
+		t.Parallel()
+
+		generated, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		key := &rsa.PrivateKey{PublicKey: generated.PublicKey, D: generated.D, Primes: generated.Primes}
+
+		jwk, err := jwt.NewJWK(key)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if key.Precomputed.Dp != nil {
+			t.Error("excepted key without precomputed values")
+		}
+
+		excepted := base64.RawURLEncoding.EncodeToString(generated.Precomputed.Qinv.Bytes())
+		if jwk.QI != excepted {
+			t.Errorf("excepted: %q, got: %q", excepted, jwk.QI)
+		}
+
+		excepted = base64.RawURLEncoding.EncodeToString(generated.Precomputed.Dp.Bytes())
+		if jwk.DP != excepted {
+			t.Errorf("excepted: %q, got: %q", excepted, jwk.DP)
+		}
+	})
+
+	t.Run("EC private key round trip", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := jwt.GenerateKey(jwt.ES512)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		jwk, err := key.JWK()
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		private, err := jwk.Key()
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if !key.Raw().(*ecdsa.PrivateKey).Equal(private) {
+			t.Error("excepted equal private keys")
+		}
+
+		if jwk.Public().IsPrivate() {
+			t.Error("excepted public jwk")
+		}
+	})
+
+	t.Run("Ed25519 private key round trip", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := jwt.GenerateKey(jwt.EdDSA)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		jwk, err := key.JWK()
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		private, err := jwk.Key()
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if !key.Raw().(ed25519.PrivateKey).Equal(private) {
+			t.Error("excepted equal private keys")
+		}
+	})
+
+	t.Run("X25519 private key round trip", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := jwt.GenerateX25519Key()
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		jwk, err := jwt.NewJWK(key)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if jwk.Curve != jwt.CurveX25519 {
+			t.Errorf("excepted: %q, got: %q", jwt.CurveX25519, jwk.Curve)
+		}
+
+		private, err := jwk.Key()
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if !bytes.Equal(key, private.(jwt.X25519PrivateKey)) {
+			t.Error("excepted equal private keys")
+		}
+	})
+
+	t.Run("EC point not on curve", func(t *testing.T) {
+		t.Parallel()
+
+		jwk := jwt.JWK{
+			KeyType: jwt.KeyTypeEC,
+			Curve:   jwt.CurveP256,
+			X:       "AQ",
+			Y:       "AQ",
+		}
+
+		_, err := jwk.Key()
+		if !errors.Is(err, jwt.ErrInvalidKey) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Unsupported key type", func(t *testing.T) {
+		t.Parallel()
+
+		jwk := jwt.JWK{KeyType: "foo"}
+
+		_, err := jwk.Key()
+		if !errors.Is(err, jwt.ErrUnsupportedKeyType) {
+			t.Error(err)
+		}
+	})
+
+	// https://datatracker.ietf.org/doc/html/rfc7638#section-3.1
+	t.Run("RFC 7638 Thumbprint", func(t *testing.T) {
+		t.Parallel()
+
+		jwk := jwt.JWK{
+			KeyType:   jwt.KeyTypeRSA,
+			N:         "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+			E:         "AQAB",
+			Algorithm: "RS256",
+			KeyID:     "2011-04-29",
+		}
+
+		excepted := "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"
+
+		thumbprint, err := jwk.Thumbprint(crypto.SHA256)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if thumbprint != excepted {
+			t.Errorf("excepted: %q, got: %q", excepted, thumbprint)
+		}
+	})
+}
@@ -0,0 +1,283 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// JSONSignature is a signature of json web signature json serialization.
+type JSONSignature struct {
+	Protected string                 `json:"protected,omitempty"`
+	Header    map[string]interface{} `json:"header,omitempty"`
+	Signature string                 `json:"signature"`
+}
+
+// JSONWebSignature is json web signature in general json serialization.
+// https://datatracker.ietf.org/doc/html/rfc7515#section-7.2
+type JSONWebSignature struct {
+	Payload    string          `json:"payload"`
+	Signatures []JSONSignature `json:"signatures"`
+
+	header Header
+}
+
+type flattenedJSONWebSignature struct {
+	Payload string `json:"payload"`
+	JSONSignature
+}
+
+// NewJSONWebSignature returns json web signature of the token without signatures.
+func NewJSONWebSignature(token Token) (*JSONWebSignature, error) {
+	payload, err := json.Marshal(token.GetPayload())
+	if err != nil {
+		return nil, fmt.Errorf("error on marshal payload: %w", err)
+	}
+
+	return &JSONWebSignature{
+		Payload:    base64.RawURLEncoding.EncodeToString(payload),
+		Signatures: []JSONSignature{},
+		header:     token.GetHeader(),
+	}, nil
+}
+
+// ParseJSON parses json web signature in general or flattened json serialization without verifying.
+func ParseJSON(data []byte) (*JSONWebSignature, error) {
+	var jws JSONWebSignature
+
+	err := json.Unmarshal(data, &jws)
+	if err != nil {
+		return nil, fmt.Errorf("invalid json web signature: %w", err)
+	}
+
+	if len(jws.Signatures) == 0 {
+		return nil, ErrInvalidToken
+	}
+
+	return &jws, nil
+}
+
+// UnmarshalJSON decodes general and flattened json serializations.
+func (s *JSONWebSignature) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Payload    *string         `json:"payload"`
+		Signatures []JSONSignature `json:"signatures"`
+		JSONSignature
+	}
+
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	if raw.Payload == nil {
+		return ErrInvalidToken
+	}
+
+	// https://datatracker.ietf.org/doc/html/rfc7515#section-7.2.2
+	flattened := raw.Protected != "" || raw.Header != nil || raw.Signature != ""
+	if flattened && raw.Signatures != nil {
+		return ErrInvalidToken
+	}
+
+	s.Payload = *raw.Payload
+	s.Signatures = raw.Signatures
+
+	if flattened {
+		s.Signatures = []JSONSignature{raw.JSONSignature}
+	}
+
+	return nil
+}
+
+// MarshalFlattened encodes the json web signature in flattened json serialization.
+// It requires exactly one signature.
+func (s JSONWebSignature) MarshalFlattened() ([]byte, error) {
+	if len(s.Signatures) != 1 {
+		return nil, ErrInvalidToken
+	}
+
+	return json.Marshal(flattenedJSONWebSignature{Payload: s.Payload, JSONSignature: s.Signatures[0]})
+}
+
+// AddSignature signs the json web signature with the signer.
+// The token header is protected and the optional unprotected header parameters must not repeat its parameters.
+func (s *JSONWebSignature) AddSignature(signer *Signer, unprotected map[string]interface{}) error {
+	return s.AddSignatureContext(context.Background(), signer, unprotected)
+}
+
+// AddSignatureContext signs the json web signature with the signer and the context passed to remote signers.
+// The protected header has the key id of the signer when it is set.
+func (s *JSONWebSignature) AddSignatureContext(ctx context.Context, signer *Signer, unprotected map[string]interface{}) error {
+	header := s.header
+	header.Algorithm = signer.alg
+
+	if signer.keyID != "" {
+		header.KeyID = signer.keyID
+	}
+
+	headerBytes, err := json.Marshal(header)
+	if err != nil {
+		return fmt.Errorf("error on marshal header: %w", err)
+	}
+
+	protected := base64.RawURLEncoding.EncodeToString(headerBytes)
+
+	sig := JSONSignature{Protected: protected, Header: unprotected}
+
+	_, err = sig.header()
+	if err != nil {
+		return err
+	}
+
+	signature, err := signWithKey(ctx, signer.alg, signer.key, sig.signingInput(s.Payload))
+	if err != nil {
+		return err
+	}
+
+	sig.Signature = base64.RawURLEncoding.EncodeToString(signature)
+	s.Signatures = append(s.Signatures, sig)
+
+	return nil
+}
+
+// Verify signatures of the json web signature.
+// It succeeds when signatures of at least minValid different keys are verified, each by a different verifier.
+// Copies of a signature and verifiers of the same key are counted once.
+func (s JSONWebSignature) Verify(minValid int, verifiers ...*Verifier) error {
+	if minValid < 1 {
+		minValid = 1
+	}
+
+	used := make([]bool, len(verifiers))
+	keys := make(map[string]bool, len(verifiers))
+	valid := 0
+
+	for _, sig := range s.Signatures {
+		header, err := sig.header()
+		if err != nil {
+			continue
+		}
+
+		signature, err := base64.RawURLEncoding.DecodeString(sig.Signature)
+		if err != nil {
+			continue
+		}
+
+		for i := range verifiers {
+			if used[i] {
+				continue
+			}
+
+			err = verifiers[i].verifySignature(header, sig.signingInput(s.Payload), signature)
+			if err == nil {
+				used[i] = true
+
+				if key := verifiers[i].keyID(i); !keys[key] {
+					keys[key] = true
+					valid++
+				}
+
+				break
+			}
+		}
+	}
+
+	if valid < minValid {
+		return ErrInvalidTokenSignature
+	}
+
+	return nil
+}
+
+// keyID returns the thumbprint of the verifier key to tell verifiers of the same key apart,
+// or the index of the verifier for keys without thumbprint.
+func (v Verifier) keyID(index int) string {
+	jwk, err := NewJWK(v.key)
+	if err != nil {
+		return fmt.Sprintf("#%d", index)
+	}
+
+	thumbprint, err := jwk.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return fmt.Sprintf("#%d", index)
+	}
+
+	return thumbprint
+}
+
+// Token returns the token of the json web signature with the header of the first signature.
+// It does not verify signatures.
+func (s JSONWebSignature) Token() (*Token, error) {
+	if len(s.Signatures) == 0 {
+		return nil, ErrInvalidToken
+	}
+
+	header, err := s.Signatures[0].header()
+	if err != nil {
+		return nil, err
+	}
+
+	tok := Token{header: *header}
+
+	payload, err := base64.RawURLEncoding.DecodeString(s.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token payload encoding: %w", err)
+	}
+
+	err = json.Unmarshal(payload, &tok.payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token payload: %w", err)
+	}
+
+	return &tok, nil
+}
+
+// header returns the union of the protected and unprotected header parameters.
+// They must be disjoint and crit and b64 must be protected.
+// https://datatracker.ietf.org/doc/html/rfc7515#section-7.2.1
+func (s JSONSignature) header() (*Header, error) {
+	protected := map[string]interface{}{}
+
+	if s.Protected != "" {
+		_, err := decodeJOSEHeader(s.Protected)
+		if err != nil {
+			return nil, err
+		}
+
+		b, _ := base64.RawURLEncoding.DecodeString(s.Protected)
+
+		err = json.Unmarshal(b, &protected)
+		if err != nil {
+			return nil, fmt.Errorf("invalid token header: %w", err)
+		}
+	}
+
+	for name := range s.Header {
+		if _, ok := protected[name]; ok || name == HeaderCritical || name == HeaderBase64 {
+			return nil, ErrInvalidToken
+		}
+
+		protected[name] = s.Header[name]
+	}
+
+	b, err := json.Marshal(protected)
+	if err != nil {
+		return nil, fmt.Errorf("error on marshal header: %w", err)
+	}
+
+	var header Header
+
+	err = json.Unmarshal(b, &header)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token header: %w", err)
+	}
+
+	return &header, nil
+}
+
+func (s JSONSignature) signingInput(payload string) []byte {
+	return []byte(fmt.Sprintf("%s.%s", s.Protected, payload))
+}
@@ -0,0 +1,356 @@
+package jwt_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestJSONWebSignature(t *testing.T) {
+	t.Parallel()
+
+	hsSigner, err := jwt.NewSigner(jwt.HS256, secret, jwt.SignWithWeakKeys())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hsVerifier, err := jwt.NewVerifier(jwt.HS256, secret, jwt.VerifyWithWeakKeys())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := jwt.GenerateKey(jwt.ES256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	esSigner, err := jwt.NewCryptoSigner(jwt.ES256, key.Raw().(crypto.Signer))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	esVerifier, err := jwt.NewPublicKeyVerifier(jwt.ES256, key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Flattened", func(t *testing.T) {
+		t.Parallel()
+
+		excepted := `{"payload":"e30","protected":"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9","signature":"HUfJqC1q8JUPKD4jj8PZAYppSrQRL8tJHTljdcTfFCQ"}`
+
+		jws, err := jwt.NewJSONWebSignature(*jwt.New(jwt.HS256))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		err = jws.AddSignature(hsSigner, nil)
+		if err != nil {
+			t.Error(err)
+		}
+
+		b, err := jws.MarshalFlattened()
+		if err != nil {
+			t.Error(err)
+		}
+
+		if string(b) != excepted {
+			t.Errorf("excepted: %q, got: %q", excepted, string(b))
+		}
+
+		parsed, err := jwt.ParseJSON(b)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		err = parsed.Verify(1, hsVerifier)
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Same Key", func(t *testing.T) {
+		t.Parallel()
+
+		otherHSVerifier, err := jwt.NewVerifier(jwt.HS256, secret, jwt.VerifyWithWeakKeys())
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		otherESVerifier, err := jwt.NewPublicKeyVerifier(jwt.ES256, key.Public())
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		jws, err := jwt.NewJSONWebSignature(*jwt.New(jwt.HS256))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		err = jws.AddSignature(hsSigner, nil)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		// copy of the signature
+		jws.Signatures = append(jws.Signatures, jws.Signatures[0])
+
+		err = jws.Verify(2, hsVerifier, otherHSVerifier)
+		if !errors.Is(err, jwt.ErrInvalidTokenSignature) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrInvalidTokenSignature, err)
+		}
+
+		// different signatures of the same key
+		for i := 0; i < 2; i++ {
+			err = jws.AddSignature(esSigner, nil)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+		}
+
+		err = jws.Verify(2, esVerifier, otherESVerifier)
+		if !errors.Is(err, jwt.ErrInvalidTokenSignature) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrInvalidTokenSignature, err)
+		}
+
+		err = jws.Verify(2, esVerifier, hsVerifier)
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("General", func(t *testing.T) {
+		t.Parallel()
+
+		token := jwt.New(jwt.HS256)
+		token.SetSubject("user")
+
+		jws, err := jwt.NewJSONWebSignature(*token)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		err = jws.AddSignature(hsSigner, map[string]interface{}{"kid": "hmac"})
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = jws.AddSignature(esSigner, map[string]interface{}{"kid": "ec"})
+		if err != nil {
+			t.Error(err)
+		}
+
+		b, err := json.Marshal(jws)
+		if err != nil {
+			t.Error(err)
+		}
+
+		parsed, err := jwt.ParseJSON(b)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if l := len(parsed.Signatures); l != 2 {
+			t.Errorf("excepted: %d, got: %d", 2, l)
+		}
+
+		if kid := parsed.Signatures[1].Header["kid"]; kid != "ec" {
+			t.Errorf("excepted: %q, got: %q", "ec", kid)
+		}
+
+		err = parsed.Verify(2, esVerifier, hsVerifier)
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = parsed.Verify(2, esVerifier)
+		if !errors.Is(err, jwt.ErrInvalidTokenSignature) {
+			t.Error(err)
+		}
+
+		err = parsed.Verify(2, esVerifier, esVerifier)
+		if !errors.Is(err, jwt.ErrInvalidTokenSignature) {
+			t.Error(err)
+		}
+
+		tok, err := parsed.Token()
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		sub, err := tok.GetSubject()
+		if err != nil {
+			t.Error(err)
+		}
+
+		if sub != "user" {
+			t.Errorf("excepted: %q, got: %q", "user", sub)
+		}
+	})
+
+	t.Run("Invalid signature", func(t *testing.T) {
+		t.Parallel()
+
+		data := `{"payload":"e30","signatures":[{"protected":"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9","signature":"ZGV2aWwK"}]}`
+
+		jws, err := jwt.ParseJSON([]byte(data))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		err = jws.Verify(1, hsVerifier)
+		if !errors.Is(err, jwt.ErrInvalidTokenSignature) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Unprotected header repeats protected header", func(t *testing.T) {
+		t.Parallel()
+
+		jws, err := jwt.NewJSONWebSignature(*jwt.New(jwt.HS256))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		err = jws.AddSignature(hsSigner, map[string]interface{}{"alg": "none"})
+		if !errors.Is(err, jwt.ErrInvalidToken) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Mixed serializations", func(t *testing.T) {
+		t.Parallel()
+
+		data := `{"payload":"e30","signature":"ZGV2aWwK","signatures":[]}`
+
+		jws, err := jwt.ParseJSON([]byte(data))
+		if !errors.Is(err, jwt.ErrInvalidToken) {
+			t.Error(err)
+		}
+
+		if jws != nil {
+			t.Errorf("excepted nil but got '%T'", jws)
+		}
+	})
+
+	// https://datatracker.ietf.org/doc/html/rfc7515#section-7.2.1
+	t.Run("Unprotected algorithm without type", func(t *testing.T) {
+		t.Parallel()
+
+		protected := base64.RawURLEncoding.EncodeToString([]byte(`{"kid":"hmac"}`))
+		payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"user"}`))
+
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(protected + "." + payload))
+
+		data := fmt.Sprintf(`{"payload":%q,"protected":%q,"header":{"alg":"HS256"},"signature":%q}`,
+			payload, protected, base64.RawURLEncoding.EncodeToString(mac.Sum(nil)))
+
+		jws, err := jwt.ParseJSON([]byte(data))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		err = jws.Verify(1, hsVerifier)
+		if err != nil {
+			t.Error(err)
+		}
+
+		tok, err := jws.Token()
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if header := tok.GetHeader(); header.Algorithm != jwt.HS256 || header.KeyID != "hmac" || header.Type != "" {
+			t.Errorf("unexcepted header %+v", header)
+		}
+	})
+
+	t.Run("Unprotected critical header", func(t *testing.T) {
+		t.Parallel()
+
+		jws, err := jwt.NewJSONWebSignature(*jwt.New(jwt.HS256))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		err = jws.AddSignature(hsSigner, map[string]interface{}{"crit": []string{"b64"}, "b64": false})
+		if !errors.Is(err, jwt.ErrInvalidToken) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Signer key id", func(t *testing.T) {
+		t.Parallel()
+
+		signer, err := jwt.NewSigner(jwt.HS256, secret, jwt.SignWithWeakKeys(), jwt.SignWithKeyID("hmac-1"))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		jws, err := jwt.NewJSONWebSignature(*jwt.New(jwt.HS256))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		err = jws.AddSignatureContext(context.Background(), signer, nil)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		tok, err := jws.Token()
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if kid := tok.GetHeader().KeyID; kid != "hmac-1" {
+			t.Errorf("excepted: %q, got: %q", "hmac-1", kid)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"context"
 	"crypto"
 	"crypto/hmac"
 	"crypto/rand"
@@ -20,11 +21,27 @@ import (
 // Header is json web token header.
 type Header struct {
 	Algorithm Algorithm `json:"alg"`
-	Type      string    `json:"typ"`
+	Type      string    `json:"typ,omitempty"`
+	KeyID     string    `json:"kid,omitempty"`
+	JWK       *JWK      `json:"jwk,omitempty"`
+	Base64    *bool     `json:"b64,omitempty"`
+	Critical  []string  `json:"crit,omitempty"`
 }
 
 const typeJWT = "JWT"
 
+// Header Parameter Names.
+const (
+	HeaderBase64   = "b64"
+	HeaderCritical = "crit"
+)
+
+// isPayloadEncoded reports whether the payload is base64url encoded, that is b64 is absent or true.
+// https://datatracker.ietf.org/doc/html/rfc7797#section-3
+func (h Header) isPayloadEncoded() bool {
+	return h.Base64 == nil || *h.Base64
+}
+
 // Payload is json web token payload.
 type Payload map[string]interface{}
 
@@ -39,12 +56,13 @@ const (
 	RS256 Algorithm = "RS256"
 	RS384 Algorithm = "RS384"
 	RS512 Algorithm = "RS512"
-	// ES256 Algorithm = "ES256"
-	// ES384 Algorithm = "ES384"
-	// ES512 Algorithm = "ES512"
-	// PS256 Algorithm = "PS256"
-	// PS384 Algorithm = "PS384"
-	// PS512 Algorithm = "PS512".
+	ES256 Algorithm = "ES256"
+	ES384 Algorithm = "ES384"
+	ES512 Algorithm = "ES512"
+	PS256 Algorithm = "PS256"
+	PS384 Algorithm = "PS384"
+	PS512 Algorithm = "PS512"
+	EdDSA Algorithm = "EdDSA"
 )
 
 // Registered Claim Names.
@@ -70,6 +88,9 @@ var (
 	ErrUnsupportedAlgorithm     = errors.New("unsupported algorithm")
 	ErrUnsupportedTokenType     = errors.New("unsupported token type")
 	ErrInvalidPem               = errors.New("invalid pem received")
+	ErrInvalidKey               = errors.New("invalid key")
+	ErrUnsupportedKeyType       = errors.New("unsupported key type")
+	ErrWeakKey                  = errors.New("key is too weak for algorithm")
 )
 
 // Token struct.
@@ -127,22 +148,7 @@ func (t *Token) SetAudience(aud ...string) {
 }
 
 func (t Token) GetAudience() ([]string, error) {
-	value, exists := t.payload[ClaimAudience]
-	if !exists {
-		return nil, ErrClaimNotFound
-	}
-
-	auds, ok := value.([]string)
-	if !ok {
-		aud, ok := value.(string)
-		if !ok {
-			return nil, ErrInvalidClaimType
-		}
-
-		return []string{aud}, nil
-	}
-
-	return auds, nil
+	return t.getStrings(ClaimAudience)
 }
 
 func (t *Token) SetExpirationTime(exp time.Time) {
@@ -150,17 +156,7 @@ func (t *Token) SetExpirationTime(exp time.Time) {
 }
 
 func (t Token) GetExpirationTime() (time.Time, error) {
-	value, exists := t.payload[ClaimExpirationTime]
-	if !exists {
-		return time.Time{}, ErrClaimNotFound
-	}
-
-	exp, ok := value.(float64)
-	if !ok {
-		return time.Time{}, ErrInvalidClaimType
-	}
-
-	return time.Unix(int64(exp), 0), nil
+	return t.getTime(ClaimExpirationTime)
 }
 
 func (t *Token) SetNotBefore(nbf time.Time) {
@@ -168,17 +164,7 @@ func (t *Token) SetNotBefore(nbf time.Time) {
 }
 
 func (t Token) GetNotBefore() (time.Time, error) {
-	value, exists := t.payload[ClaimNotBefore]
-	if !exists {
-		return time.Time{}, ErrClaimNotFound
-	}
-
-	nbf, ok := value.(float64)
-	if !ok {
-		return time.Time{}, ErrInvalidClaimType
-	}
-
-	return time.Unix(int64(nbf), 0), nil
+	return t.getTime(ClaimNotBefore)
 }
 
 func (t *Token) SetIssuedAt(iat time.Time) {
@@ -186,17 +172,24 @@ func (t *Token) SetIssuedAt(iat time.Time) {
 }
 
 func (t Token) GetIssuedAt() (time.Time, error) {
-	value, exists := t.payload[ClaimIssuedAt]
+	return t.getTime(ClaimIssuedAt)
+}
+
+// getTime returns claim of numeric date, either decoded from json or set by the setters.
+func (t Token) getTime(key string) (time.Time, error) {
+	value, exists := t.payload[key]
 	if !exists {
 		return time.Time{}, ErrClaimNotFound
 	}
 
-	iat, ok := value.(float64)
-	if !ok {
+	switch v := value.(type) {
+	case float64:
+		return time.Unix(int64(v), 0), nil
+	case int64:
+		return time.Unix(v, 0), nil
+	default:
 		return time.Time{}, ErrInvalidClaimType
 	}
-
-	return time.Unix(int64(iat), 0), nil
 }
 
 func (t *Token) SetJWTID(jti string) {
@@ -261,9 +254,16 @@ func New(alg Algorithm) *Token {
 
 // Sign the token with secret key.
 func Sign(token Token, key []byte) (string, error) {
-	header := token.GetHeader()
+	unsignedToken, err := encodeUnsignedToken(token)
+	if err != nil {
+		return "", err
+	}
+
+	return signByAlgorithm(token.header.Algorithm, key, unsignedToken)
+}
 
-	headerBytes, err := json.Marshal(header)
+func encodeUnsignedToken(token Token) (string, error) {
+	headerBytes, err := json.Marshal(token.GetHeader())
 	if err != nil {
 		return "", fmt.Errorf("error on marshal header: %w", err)
 	}
@@ -275,9 +275,8 @@ func Sign(token Token, key []byte) (string, error) {
 
 	encodedHeader := base64.RawURLEncoding.EncodeToString(headerBytes)
 	encodedPayload := base64.RawURLEncoding.EncodeToString(payloadBytes)
-	unsignedToken := fmt.Sprintf("%s.%s", encodedHeader, encodedPayload)
 
-	return signByAlgorithm(header.Algorithm, key, unsignedToken)
+	return fmt.Sprintf("%s.%s", encodedHeader, encodedPayload), nil
 }
 
 func signByAlgorithm(alg Algorithm, key []byte, unsignedToken string) (string, error) {
@@ -294,6 +293,8 @@ func signByAlgorithm(alg Algorithm, key []byte, unsignedToken string) (string, e
 		return signRS384(key, unsignedToken)
 	case RS512:
 		return signRS512(key, unsignedToken)
+	case PS256, PS384, PS512, ES256, ES384, ES512, EdDSA:
+		return signByPrivateKey(alg, key, unsignedToken)
 	default:
 		return "", ErrUnsupportedAlgorithm
 	}
@@ -383,6 +384,25 @@ func signRS512(key []byte, unsignedToken string) (string, error) {
 	return fmt.Sprintf("%s.%s", unsignedToken, base64.RawURLEncoding.EncodeToString(b)), nil
 }
 
+func signByPrivateKey(alg Algorithm, key []byte, unsignedToken string) (string, error) {
+	private, err := parsePrivateKeyPEM(key)
+	if err != nil {
+		return "", err
+	}
+
+	err = checkKey(alg, private.Public(), true)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := signWithKey(context.Background(), alg, cryptoSigner{private}, []byte(unsignedToken))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s.%s", unsignedToken, base64.RawURLEncoding.EncodeToString(b)), nil
+}
+
 // Verify token string with secret key.
 func Verify(t string, key []byte) error {
 	arr := strings.Split(t, ".")
@@ -390,26 +410,80 @@ func Verify(t string, key []byte) error {
 		return ErrInvalidToken
 	}
 
-	var tok Token
-
-	header, err := base64.RawURLEncoding.DecodeString(arr[0])
+	header, err := decodeHeader(arr[0])
 	if err != nil {
-		return fmt.Errorf("invalid token header encoding: %w", err)
+		return err
 	}
 
-	err = json.Unmarshal(header, &tok.header)
+	unsignedToken := fmt.Sprintf("%s.%s", arr[0], arr[1])
+
+	return verifyByAlgorithm(header.Algorithm, key, unsignedToken, arr[2])
+}
+
+func decodeHeader(encodedHeader string) (*Header, error) {
+	header, err := decodeJOSEHeader(encodedHeader)
 	if err != nil {
-		return fmt.Errorf("invalid token header: %w", err)
+		return nil, err
 	}
 
 	// https://datatracker.ietf.org/doc/html/rfc7519#section-5.1
-	if typ := tok.header.Type; strings.ToUpper(typ) != typeJWT {
-		return ErrUnsupportedTokenType
+	if typ := header.Type; strings.ToUpper(typ) != typeJWT {
+		return nil, ErrUnsupportedTokenType
 	}
 
-	unsignedToken := fmt.Sprintf("%s.%s", arr[0], arr[1])
+	// json web token claims are always base64url encoded
+	if !header.isPayloadEncoded() {
+		return nil, ErrInvalidToken
+	}
+
+	return header, nil
+}
+
+// decodeJOSEHeader decodes header of any json web signature and checks its critical parameters.
+func decodeJOSEHeader(encodedHeader string) (*Header, error) {
+	var header Header
 
-	return verifyByAlgorithm(tok.header.Algorithm, key, unsignedToken, arr[2])
+	b, err := base64.RawURLEncoding.DecodeString(encodedHeader)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token header encoding: %w", err)
+	}
+
+	err = json.Unmarshal(b, &header)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token header: %w", err)
+	}
+
+	err = checkCritical(header)
+	if err != nil {
+		return nil, err
+	}
+
+	return &header, nil
+}
+
+// checkCritical rejects headers with critical parameters this package does not understand.
+// https://datatracker.ietf.org/doc/html/rfc7515#section-4.1.11
+func checkCritical(header Header) error {
+	if header.Critical != nil && len(header.Critical) == 0 {
+		return ErrInvalidToken
+	}
+
+	b64 := false
+
+	for _, name := range header.Critical {
+		if name != HeaderBase64 || header.Base64 == nil {
+			return ErrInvalidToken
+		}
+
+		b64 = true
+	}
+
+	// https://datatracker.ietf.org/doc/html/rfc7797#section-6
+	if header.Base64 != nil && !b64 {
+		return ErrInvalidToken
+	}
+
+	return nil
 }
 
 func verifyByAlgorithm(alg Algorithm, key []byte, unsignedToken, signature string) error {
@@ -426,6 +500,8 @@ func verifyByAlgorithm(alg Algorithm, key []byte, unsignedToken, signature strin
 		return verifyRS384(key, unsignedToken, signature)
 	case RS512:
 		return verifyRS512(key, unsignedToken, signature)
+	case PS256, PS384, PS512, ES256, ES384, ES512, EdDSA:
+		return verifyByPublicKey(alg, key, unsignedToken, signature)
 	default:
 		return ErrUnsupportedAlgorithm
 	}
@@ -557,6 +633,25 @@ func verifyRS512(key []byte, unsignedToken, signature string) error {
 	return nil
 }
 
+func verifyByPublicKey(alg Algorithm, key []byte, unsignedToken, signature string) error {
+	public, err := parsePublicKeyPEM(key)
+	if err != nil {
+		return err
+	}
+
+	err = checkKey(alg, public, true)
+	if err != nil {
+		return err
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return ErrInvalidTokenSignature
+	}
+
+	return verifyWithKey(alg, public, []byte(unsignedToken), sig)
+}
+
 // Parse token string without verifying.
 func Parse(t string) (*Token, error) {
 	arr := strings.Split(t, ".")
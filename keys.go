@@ -0,0 +1,210 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+)
+
+// DefaultRSAKeyBits is the size of rsa keys created by GenerateKey.
+const DefaultRSAKeyBits = 2048
+
+// Key is a key generated for an algorithm.
+type Key struct {
+	alg Algorithm
+	key interface{}
+}
+
+// GenerateKey returns a new random key for signing with the algorithm.
+func GenerateKey(alg Algorithm) (*Key, error) {
+	var (
+		key interface{}
+		err error
+	)
+
+	switch alg {
+	case HS256:
+		key, err = generateSecret(sha256.Size)
+	case HS384:
+		key, err = generateSecret(sha512.Size384)
+	case HS512:
+		key, err = generateSecret(sha512.Size)
+	case RS256, RS384, RS512, PS256, PS384, PS512:
+		key, err = rsa.GenerateKey(rand.Reader, DefaultRSAKeyBits)
+	case ES256:
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case ES384:
+		key, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case ES512:
+		key, err = ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	case EdDSA:
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error on generate key: %w", err)
+	}
+
+	return &Key{alg: alg, key: key}, nil
+}
+
+func generateSecret(size int) ([]byte, error) {
+	secret := make([]byte, size)
+
+	_, err := rand.Read(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+// Algorithm returns the algorithm the key is generated for.
+func (k Key) Algorithm() Algorithm {
+	return k.alg
+}
+
+// Raw returns the underlying key.
+// It is a []byte for HS algorithms, otherwise a *rsa.PrivateKey, *ecdsa.PrivateKey or ed25519.PrivateKey.
+func (k Key) Raw() interface{} {
+	return k.key
+}
+
+// Public returns the public key, or nil for symmetric keys.
+func (k Key) Public() crypto.PublicKey {
+	signer, ok := k.key.(crypto.Signer)
+	if !ok {
+		return nil
+	}
+
+	return signer.Public()
+}
+
+// PrivateKeyPEM returns the private key encoded as pem.
+// RSA keys are encoded as PKCS #1, EC keys as SEC 1 and Ed25519 keys as PKCS #8.
+func (k Key) PrivateKeyPEM() ([]byte, error) {
+	var block pem.Block
+
+	switch key := k.key.(type) {
+	case *rsa.PrivateKey:
+		block = pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	case *ecdsa.PrivateKey:
+		b, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("error on marshal private key: %w", err)
+		}
+
+		block = pem.Block{Type: "EC PRIVATE KEY", Bytes: b}
+	case ed25519.PrivateKey:
+		b, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("error on marshal private key: %w", err)
+		}
+
+		block = pem.Block{Type: "PRIVATE KEY", Bytes: b}
+	default:
+		return nil, ErrUnsupportedKeyType
+	}
+
+	return pem.EncodeToMemory(&block), nil
+}
+
+// PublicKeyPEM returns the public key encoded as PKIX pem.
+func (k Key) PublicKeyPEM() ([]byte, error) {
+	public := k.Public()
+	if public == nil {
+		return nil, ErrUnsupportedKeyType
+	}
+
+	b, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return nil, fmt.Errorf("error on marshal public key: %w", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b}), nil
+}
+
+// JWK returns the key as json web key.
+func (k Key) JWK() (*JWK, error) {
+	jwk, err := NewJWK(k.key)
+	if err != nil {
+		return nil, err
+	}
+
+	jwk.Algorithm = string(k.alg)
+
+	return jwk, nil
+}
+
+func parsePrivateKeyPEM(key []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		return nil, ErrInvalidPem
+	}
+
+	var (
+		private interface{}
+		err     error
+	)
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		private, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error on parse private key: %w", err)
+	}
+
+	signer, ok := private.(crypto.Signer)
+	if !ok {
+		return nil, ErrUnsupportedKeyType
+	}
+
+	return signer, nil
+}
+
+func parsePublicKeyPEM(key []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		return nil, ErrInvalidPem
+	}
+
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		public, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error on parse public key: %w", err)
+		}
+
+		return public, nil
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error on parse certificate: %w", err)
+		}
+
+		return cert.PublicKey, nil
+	default:
+		public, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error on parse public key: %w", err)
+		}
+
+		return public, nil
+	}
+}
@@ -0,0 +1,256 @@
+package jwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestGenerateKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Generate invalid algorithm", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := jwt.GenerateKey("foo")
+		if err == nil {
+			t.Error("excepted error but got nil")
+		}
+
+		if !errors.Is(err, jwt.ErrUnsupportedAlgorithm) {
+			t.Error(err)
+		}
+
+		if key != nil {
+			t.Errorf("excepted nil but got '%T'", key)
+		}
+	})
+
+	for alg, size := range map[jwt.Algorithm]int{jwt.HS256: 32, jwt.HS384: 48, jwt.HS512: 64} {
+		alg, size := alg, size
+
+		t.Run("Generate "+string(alg), func(t *testing.T) {
+			t.Parallel()
+
+			key, err := jwt.GenerateKey(alg)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			secret, ok := key.Raw().([]byte)
+			if !ok {
+				t.Errorf("excepted []byte but got '%T'", key.Raw())
+
+				return
+			}
+
+			if len(secret) != size {
+				t.Errorf("excepted: %d, got: %d", size, len(secret))
+			}
+
+			if key.Public() != nil {
+				t.Errorf("excepted nil but got '%T'", key.Public())
+			}
+
+			_, err = key.PrivateKeyPEM()
+			if !errors.Is(err, jwt.ErrUnsupportedKeyType) {
+				t.Error(err)
+			}
+
+			tokenStr, err := jwt.Sign(*jwt.New(alg), secret)
+			if err != nil {
+				t.Error(err)
+			}
+
+			err = jwt.Verify(tokenStr, secret)
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	for _, alg := range []jwt.Algorithm{jwt.RS256, jwt.RS384, jwt.RS512} {
+		alg := alg
+
+		t.Run("Generate "+string(alg), func(t *testing.T) {
+			t.Parallel()
+
+			key, err := jwt.GenerateKey(alg)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			private, ok := key.Raw().(*rsa.PrivateKey)
+			if !ok {
+				t.Errorf("excepted *rsa.PrivateKey but got '%T'", key.Raw())
+
+				return
+			}
+
+			if bits := private.N.BitLen(); bits != jwt.DefaultRSAKeyBits {
+				t.Errorf("excepted: %d, got: %d", jwt.DefaultRSAKeyBits, bits)
+			}
+
+			privatePEM, err := key.PrivateKeyPEM()
+			if err != nil {
+				t.Error(err)
+			}
+
+			publicPEM, err := key.PublicKeyPEM()
+			if err != nil {
+				t.Error(err)
+			}
+
+			tokenStr, err := jwt.Sign(*jwt.New(alg), privatePEM)
+			if err != nil {
+				t.Error(err)
+			}
+
+			err = jwt.Verify(tokenStr, publicPEM)
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	t.Run("Generate PS256", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := jwt.GenerateKey(jwt.PS256)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		private, ok := key.Raw().(*rsa.PrivateKey)
+		if !ok {
+			t.Errorf("excepted *rsa.PrivateKey but got '%T'", key.Raw())
+
+			return
+		}
+
+		if bits := private.N.BitLen(); bits != jwt.DefaultRSAKeyBits {
+			t.Errorf("excepted: %d, got: %d", jwt.DefaultRSAKeyBits, bits)
+		}
+	})
+
+	for alg, bits := range map[jwt.Algorithm]int{jwt.ES256: 256, jwt.ES384: 384, jwt.ES512: 521} {
+		alg, bits := alg, bits
+
+		t.Run("Generate "+string(alg), func(t *testing.T) {
+			t.Parallel()
+
+			key, err := jwt.GenerateKey(alg)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			private, ok := key.Raw().(*ecdsa.PrivateKey)
+			if !ok {
+				t.Errorf("excepted *ecdsa.PrivateKey but got '%T'", key.Raw())
+
+				return
+			}
+
+			if size := private.Curve.Params().BitSize; size != bits {
+				t.Errorf("excepted: %d, got: %d", bits, size)
+			}
+
+			privatePEM, err := key.PrivateKeyPEM()
+			if err != nil {
+				t.Error(err)
+			}
+
+			block, _ := pem.Decode(privatePEM)
+			if block == nil {
+				t.Error("excepted pem block but got nil")
+
+				return
+			}
+
+			_, err = x509.ParseECPrivateKey(block.Bytes)
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	t.Run("Generate EdDSA", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := jwt.GenerateKey(jwt.EdDSA)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if _, ok := key.Raw().(ed25519.PrivateKey); !ok {
+			t.Errorf("excepted ed25519.PrivateKey but got '%T'", key.Raw())
+		}
+
+		publicPEM, err := key.PublicKeyPEM()
+		if err != nil {
+			t.Error(err)
+		}
+
+		block, _ := pem.Decode(publicPEM)
+		if block == nil {
+			t.Error("excepted pem block but got nil")
+
+			return
+		}
+
+		public, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if !key.Public().(ed25519.PublicKey).Equal(public) {
+			t.Error("excepted equal public keys")
+		}
+	})
+
+	t.Run("Export JWK", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := jwt.GenerateKey(jwt.ES256)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		jwk, err := key.JWK()
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if jwk.Algorithm != string(jwt.ES256) {
+			t.Errorf("excepted: %q, got: %q", jwt.ES256, jwk.Algorithm)
+		}
+
+		if jwk.KeyType != jwt.KeyTypeEC || jwk.Curve != jwt.CurveP256 {
+			t.Errorf("excepted: %q %q, got: %q %q", jwt.KeyTypeEC, jwt.CurveP256, jwk.KeyType, jwk.Curve)
+		}
+
+		if !jwk.IsPrivate() {
+			t.Error("excepted private jwk")
+		}
+	})
+}
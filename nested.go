@@ -0,0 +1,72 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotNestedToken is returned when the decrypted token content type is not JWT.
+var ErrNotNestedToken = errors.New("token is not nested json web token")
+
+// EncryptNested encrypts the signed token with cty JWT header parameter.
+// https://datatracker.ietf.org/doc/html/rfc7519#section-5.2
+func (e Encrypter) EncryptNested(signedToken string) (string, error) {
+	header := e.header
+	header.ContentType = typeJWT
+
+	return e.encrypt(header, []byte(signedToken))
+}
+
+// SignAndEncrypt signs the token with the signer and encrypts the signed token.
+// https://datatracker.ietf.org/doc/html/rfc7519#section-11.2
+func SignAndEncrypt(token Token, signer Signer, encrypter Encrypter) (string, error) {
+	signedToken, err := signer.Sign(token)
+	if err != nil {
+		return "", fmt.Errorf("error on sign token: %w", err)
+	}
+
+	t, err := encrypter.EncryptNested(signedToken)
+	if err != nil {
+		return "", fmt.Errorf("error on encrypt token: %w", err)
+	}
+
+	return t, nil
+}
+
+// DecryptNested decrypts nested json web token and returns the signed token.
+func (d Decrypter) DecryptNested(t string) (string, error) {
+	header, plaintext, err := d.decrypt(t)
+	if err != nil {
+		return "", err
+	}
+
+	if strings.ToUpper(header.ContentType) != typeJWT {
+		return "", ErrNotNestedToken
+	}
+
+	return string(plaintext), nil
+}
+
+// DecryptAndVerify decrypts nested json web token, verifies the signed token with the verifier,
+// validates its claims and returns it. The signed token must be of the algorithm of the verifier,
+// so that the algorithm of its header cannot be chosen by whoever encrypted it.
+// Errors are prefixed by the layer that failed: decryption, signature or claims.
+func DecryptAndVerify(t string, decrypter Decrypter, verifier TokenVerifier) (*Token, error) {
+	signedToken, err := decrypter.DecryptNested(t)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token encryption: %w", err)
+	}
+
+	token, err := verifier.VerifyType(signedToken, "", typeJWT)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nested token signature: %w", err)
+	}
+
+	err = token.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid nested token claims: %w", err)
+	}
+
+	return token, nil
+}
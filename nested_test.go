@@ -0,0 +1,210 @@
+package jwt_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestDecryptAndVerify(t *testing.T) {
+	t.Parallel()
+
+	encryptionKey := strongSecret[:32]
+
+	encrypter, err := jwt.NewEncrypter(jwt.A256KW, jwt.A256GCM, encryptionKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decrypter, err := jwt.NewDecrypter(jwt.A256KW, encryptionKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsaSigner, err := jwt.NewSigner(jwt.RS256, []byte(private))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsaVerifier, err := jwt.NewVerifier(jwt.RS256, []byte(public))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hmacSigner, err := jwt.NewSigner(jwt.HS256, strongSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hmacVerifier, err := jwt.NewVerifier(jwt.HS256, strongSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Round Trip", func(t *testing.T) {
+		t.Parallel()
+
+		token := jwt.New(jwt.RS256)
+		token.SetSubject("alice")
+		token.SetExpirationTime(time.Now().Add(time.Hour))
+
+		tokenStr, err := jwt.SignAndEncrypt(*token, *rsaSigner, *encrypter)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		signedToken, err := decrypter.DecryptNested(tokenStr)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if parts := len(strings.Split(signedToken, ".")); parts != 3 {
+			t.Errorf("excepted: %d, got: %d", 3, parts)
+		}
+
+		res, err := jwt.DecryptAndVerify(tokenStr, *decrypter, *rsaVerifier)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		sub, err := res.GetSubject()
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if sub != "alice" {
+			t.Errorf("excepted: %q, got: %q", "alice", sub)
+		}
+	})
+
+	t.Run("Encryption Layer", func(t *testing.T) {
+		t.Parallel()
+
+		tokenStr, err := jwt.SignAndEncrypt(*jwt.New(jwt.HS256), *hmacSigner, *encrypter)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		otherDecrypter, err := jwt.NewDecrypter(jwt.A256KW, []byte("fedcba9876543210fedcba9876543210"))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_, err = jwt.DecryptAndVerify(tokenStr, *otherDecrypter, *hmacVerifier)
+		if !errors.Is(err, jwt.ErrDecryptionFailed) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrDecryptionFailed, err)
+		}
+
+		if err != nil && !strings.HasPrefix(err.Error(), "invalid token encryption") {
+			t.Errorf("excepted encryption layer error, got %q", err)
+		}
+	})
+
+	t.Run("Not Nested", func(t *testing.T) {
+		t.Parallel()
+
+		tokenStr, err := encrypter.Encrypt([]byte("plain"))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_, err = jwt.DecryptAndVerify(tokenStr, *decrypter, *hmacVerifier)
+		if !errors.Is(err, jwt.ErrNotNestedToken) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrNotNestedToken, err)
+		}
+	})
+
+	t.Run("Signature Layer", func(t *testing.T) {
+		t.Parallel()
+
+		tokenStr, err := jwt.SignAndEncrypt(*jwt.New(jwt.HS256), *hmacSigner, *encrypter)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		otherVerifier, err := jwt.NewVerifier(jwt.HS256, []byte(strings.Repeat("k", 64)))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_, err = jwt.DecryptAndVerify(tokenStr, *decrypter, *otherVerifier)
+		if !errors.Is(err, jwt.ErrInvalidTokenSignature) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrInvalidTokenSignature, err)
+		}
+
+		if err != nil && !strings.HasPrefix(err.Error(), "invalid nested token signature") {
+			t.Errorf("excepted signature layer error, got %q", err)
+		}
+	})
+
+	// An HS256 token signed with the RSA public key as the secret must not verify as the RS256 token of the key.
+	t.Run("Algorithm Confusion", func(t *testing.T) {
+		t.Parallel()
+
+		token := jwt.New(jwt.HS256)
+		token.Set("admin", true)
+
+		forged, err := jwt.Sign(*token, []byte(public))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		tokenStr, err := encrypter.EncryptNested(forged)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_, err = jwt.DecryptAndVerify(tokenStr, *decrypter, *rsaVerifier)
+		if !errors.Is(err, jwt.ErrUnsupportedAlgorithm) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrUnsupportedAlgorithm, err)
+		}
+	})
+
+	t.Run("Claims Layer", func(t *testing.T) {
+		t.Parallel()
+
+		token := jwt.New(jwt.HS256)
+		token.SetExpirationTime(time.Now().Add(-time.Hour))
+
+		tokenStr, err := jwt.SignAndEncrypt(*token, *hmacSigner, *encrypter)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_, err = jwt.DecryptAndVerify(tokenStr, *decrypter, *hmacVerifier)
+		if !errors.Is(err, jwt.ErrTokenExpired) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrTokenExpired, err)
+		}
+
+		if err != nil && !strings.HasPrefix(err.Error(), "invalid nested token claims") {
+			t.Errorf("excepted claims layer error, got %q", err)
+		}
+	})
+}
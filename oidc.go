@@ -0,0 +1,338 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// OpenID Connect Claim Names.
+// https://openid.net/specs/openid-connect-core-1_0.html#IDToken
+const (
+	ClaimAuthTime        = "auth_time"
+	ClaimNonce           = "nonce"
+	ClaimACR             = "acr"
+	ClaimAMR             = "amr"
+	ClaimAuthorizedParty = "azp"
+	ClaimAccessTokenHash = "at_hash"
+	ClaimCodeHash        = "c_hash"
+)
+
+var (
+	ErrInvalidIssuer   = errors.New("invalid token issuer")
+	ErrInvalidAudience = errors.New("invalid token audience")
+	ErrInvalidNonce    = errors.New("invalid token nonce")
+	ErrAuthTimeExpired = errors.New("authentication time exceeds max age")
+	ErrInvalidACR      = errors.New("invalid authentication context class reference")
+	ErrInvalidHash     = errors.New("invalid token hash")
+)
+
+// IDTokenClaims is typed claims of OpenID Connect ID token.
+// https://openid.net/specs/openid-connect-core-1_0.html#StandardClaims
+type IDTokenClaims struct {
+	Issuer          string    `json:"iss"`
+	Subject         string    `json:"sub"`
+	Audience        []string  `json:"-"`
+	ExpirationTime  time.Time `json:"-"`
+	IssuedAt        time.Time `json:"-"`
+	AuthTime        time.Time `json:"-"`
+	Nonce           string    `json:"nonce,omitempty"`
+	ACR             string    `json:"acr,omitempty"`
+	AMR             []string  `json:"amr,omitempty"`
+	AuthorizedParty string    `json:"azp,omitempty"`
+
+	Name                string `json:"name,omitempty"`
+	GivenName           string `json:"given_name,omitempty"`
+	FamilyName          string `json:"family_name,omitempty"`
+	PreferredUsername   string `json:"preferred_username,omitempty"`
+	Picture             string `json:"picture,omitempty"`
+	Locale              string `json:"locale,omitempty"`
+	Email               string `json:"email,omitempty"`
+	EmailVerified       bool   `json:"email_verified,omitempty"`
+	PhoneNumber         string `json:"phone_number,omitempty"`
+	PhoneNumberVerified bool   `json:"phone_number_verified,omitempty"`
+}
+
+// UnmarshalJSON decodes ID token claims with email_verified and phone_number_verified of a boolean or
+// a "true" or "false" string, as some providers send them.
+func (c *IDTokenClaims) UnmarshalJSON(b []byte) error {
+	type claims IDTokenClaims
+
+	var v struct {
+		claims
+		EmailVerified       interface{} `json:"email_verified,omitempty"`
+		PhoneNumberVerified interface{} `json:"phone_number_verified,omitempty"`
+	}
+
+	err := json.Unmarshal(b, &v)
+	if err != nil {
+		return err
+	}
+
+	*c = IDTokenClaims(v.claims)
+
+	c.EmailVerified, err = parseBoolClaim(v.EmailVerified)
+	if err != nil {
+		return fmt.Errorf("invalid email_verified: %w", err)
+	}
+
+	c.PhoneNumberVerified, err = parseBoolClaim(v.PhoneNumberVerified)
+	if err != nil {
+		return fmt.Errorf("invalid phone_number_verified: %w", err)
+	}
+
+	return nil
+}
+
+func parseBoolClaim(v interface{}) (bool, error) {
+	switch b := v.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return b, nil
+	case string:
+		switch b {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+	}
+
+	return false, ErrInvalidClaimType
+}
+
+// IDTokenValidator validates OpenID Connect ID tokens issued by an issuer to a client.
+// https://openid.net/specs/openid-connect-core-1_0.html#IDTokenValidation
+type IDTokenValidator struct {
+	issuer        string
+	clientID      string
+	nonce         string
+	maxAge        time.Duration
+	acrValues     []string
+	accessToken   string
+	code          string
+	requireHashes bool
+}
+
+// IDTokenOption configures ID token validation.
+type IDTokenOption func(*IDTokenValidator)
+
+// IDTokenWithNonce requires nonce claim to be the nonce of the authentication request.
+func IDTokenWithNonce(nonce string) IDTokenOption {
+	return func(v *IDTokenValidator) {
+		v.nonce = nonce
+	}
+}
+
+// IDTokenWithMaxAge requires auth_time claim not to be older than max age of the authentication request.
+func IDTokenWithMaxAge(maxAge time.Duration) IDTokenOption {
+	return func(v *IDTokenValidator) {
+		v.maxAge = maxAge
+	}
+}
+
+// IDTokenWithACRValues requires acr claim to be any of the values.
+func IDTokenWithACRValues(values ...string) IDTokenOption {
+	return func(v *IDTokenValidator) {
+		v.acrValues = values
+	}
+}
+
+// IDTokenWithAccessToken requires at_hash claim, if present, to match the access token issued with the ID token.
+// The claim is required with IDTokenWithRequiredHashes.
+// https://openid.net/specs/openid-connect-core-1_0.html#CodeIDToken
+func IDTokenWithAccessToken(accessToken string) IDTokenOption {
+	return func(v *IDTokenValidator) {
+		v.accessToken = accessToken
+	}
+}
+
+// IDTokenWithCode requires c_hash claim, if present, to match the authorization code issued with the ID token.
+// The claim is required with IDTokenWithRequiredHashes.
+// https://openid.net/specs/openid-connect-core-1_0.html#HybridIDToken
+func IDTokenWithCode(code string) IDTokenOption {
+	return func(v *IDTokenValidator) {
+		v.code = code
+	}
+}
+
+// IDTokenWithRequiredHashes requires at_hash and c_hash claims of the access token and code given to the validator,
+// e.g. for ID tokens of the authorization endpoint in hybrid flow.
+// https://openid.net/specs/openid-connect-core-1_0.html#HybridIDToken
+func IDTokenWithRequiredHashes() IDTokenOption {
+	return func(v *IDTokenValidator) {
+		v.requireHashes = true
+	}
+}
+
+// NewIDTokenValidator returns ID token validator of the issuer and the client.
+func NewIDTokenValidator(issuer, clientID string, opts ...IDTokenOption) *IDTokenValidator {
+	v := IDTokenValidator{
+		issuer:   issuer,
+		clientID: clientID,
+	}
+
+	for i := range opts {
+		opts[i](&v)
+	}
+
+	return &v
+}
+
+// Verify verifies ID token string with the verifier and validates it.
+// Options are applied for this token only, e.g. the nonce of its authentication request.
+func (v IDTokenValidator) Verify(t string, verifier TokenVerifier, opts ...IDTokenOption) (*IDTokenClaims, error) {
+	token, err := verifier.VerifyType(t, "", typeJWT)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.Validate(*token, opts...)
+}
+
+// Validate the claims of verified ID token and return them.
+// Options are applied for this token only, e.g. the nonce of its authentication request.
+func (v IDTokenValidator) Validate(token Token, opts ...IDTokenOption) (*IDTokenClaims, error) {
+	for i := range opts {
+		opts[i](&v)
+	}
+
+	claims, err := idTokenClaims(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Issuer != v.issuer {
+		return nil, ErrInvalidIssuer
+	}
+
+	// https://openid.net/specs/openid-connect-core-1_0.html#IDTokenValidation
+	if !containsAll(claims.Audience, []string{v.clientID}) {
+		return nil, ErrInvalidAudience
+	}
+
+	if (len(claims.Audience) > 1 || claims.AuthorizedParty != "") && claims.AuthorizedParty != v.clientID {
+		return nil, ErrInvalidAudience
+	}
+
+	err = token.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if v.nonce != "" && subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(v.nonce)) != 1 {
+		return nil, ErrInvalidNonce
+	}
+
+	if v.maxAge > 0 {
+		if claims.AuthTime.IsZero() {
+			return nil, fmt.Errorf("%w: %s", ErrClaimNotFound, ClaimAuthTime)
+		}
+
+		if time.Since(claims.AuthTime) > v.maxAge {
+			return nil, ErrAuthTimeExpired
+		}
+	}
+
+	if len(v.acrValues) > 0 && !containsAll(v.acrValues, []string{claims.ACR}) {
+		return nil, ErrInvalidACR
+	}
+
+	err = v.validateHashes(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func (v IDTokenValidator) validateHashes(token Token) error {
+	for claim, value := range map[string]string{ClaimAccessTokenHash: v.accessToken, ClaimCodeHash: v.code} {
+		if value == "" {
+			continue
+		}
+
+		h, err := token.Get(claim)
+		if errors.Is(err, ErrClaimNotFound) {
+			if v.requireHashes {
+				return fmt.Errorf("%w: %s", ErrClaimNotFound, claim)
+			}
+
+			continue
+		}
+
+		expected, err := TokenHash(token.header.Algorithm, value)
+		if err != nil {
+			return err
+		}
+
+		if hash, ok := h.(string); !ok || subtle.ConstantTimeCompare([]byte(hash), []byte(expected)) != 1 {
+			return fmt.Errorf("%w: %s", ErrInvalidHash, claim)
+		}
+	}
+
+	return nil
+}
+
+// TokenHash returns at_hash or c_hash value of the access token or code for ID tokens signed with the algorithm,
+// the base64url encoded left-most half of the hash of the algorithm.
+// https://openid.net/specs/openid-connect-core-1_0.html#CodeIDToken
+func TokenHash(alg Algorithm, value string) (string, error) {
+	hash, err := hashByAlgorithm(alg)
+	if alg == EdDSA {
+		hash, err = crypto.SHA512, nil
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	sum := digest(hash, []byte(value))
+
+	return encodeBytes(sum[:len(sum)/2]), nil
+}
+
+func idTokenClaims(token Token) (*IDTokenClaims, error) {
+	b, err := json.Marshal(token.payload)
+	if err != nil {
+		return nil, fmt.Errorf("error on marshal payload: %w", err)
+	}
+
+	var claims IDTokenClaims
+
+	err = json.Unmarshal(b, &claims)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidClaimType, err)
+	}
+
+	err = requireClaims(token, ClaimIssuer, ClaimSubject, ClaimAudience, ClaimExpirationTime, ClaimIssuedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	claims.Audience, err = token.GetAudience()
+	if err != nil {
+		return nil, err
+	}
+
+	claims.ExpirationTime, err = token.GetExpirationTime()
+	if err != nil {
+		return nil, err
+	}
+
+	claims.IssuedAt, err = token.GetIssuedAt()
+	if err != nil {
+		return nil, err
+	}
+
+	claims.AuthTime, err = token.getTime(ClaimAuthTime)
+	if err != nil && !errors.Is(err, ErrClaimNotFound) {
+		return nil, err
+	}
+
+	return &claims, nil
+}
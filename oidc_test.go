@@ -0,0 +1,227 @@
+package jwt_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestTokenHash(t *testing.T) {
+	t.Parallel()
+
+	// https://openid.net/specs/openid-connect-core-1_0.html#code-id_tokenExample
+	hash, err := jwt.TokenHash(jwt.RS256, "Qcb0Orv1zh30vL1MPRsbm-diHiMwcLyZvn1arpZv-Jxf_11jnpEX3Tgfvk")
+	if err != nil {
+		t.Error(err)
+
+		return
+	}
+
+	if hash != "LDktKdoQak3Pk0cnXxCltA" {
+		t.Errorf("excepted: %q, got: %q", "LDktKdoQak3Pk0cnXxCltA", hash)
+	}
+
+	for _, alg := range []jwt.Algorithm{jwt.HS384, jwt.ES512, jwt.EdDSA} {
+		hash, err := jwt.TokenHash(alg, "access-token")
+		if err != nil {
+			t.Error(err)
+
+			continue
+		}
+
+		if hash == "LDktKdoQak3Pk0cnXxCltA" || len(hash) < 32 {
+			t.Errorf("excepted half of %s hash, got: %q", alg, hash)
+		}
+	}
+}
+
+func TestIDTokenValidator(t *testing.T) {
+	t.Parallel()
+
+	const (
+		issuer   = "https://server.example.com"
+		clientID = "s6BhdRkqt3"
+	)
+
+	signer, err := jwt.NewSigner(jwt.RS256, []byte(private))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := jwt.NewVerifier(jwt.RS256, []byte(public))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	atHash, err := jwt.TokenHash(jwt.RS256, "access-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idToken := func(modify func(token *jwt.Token)) string {
+		token := jwt.New(jwt.RS256)
+		token.SetIssuer(issuer)
+		token.SetSubject("24400320")
+		token.SetAudience(clientID)
+		token.SetIssuedAt(time.Now())
+		token.SetExpirationTime(time.Now().Add(time.Hour))
+		token.Set(jwt.ClaimNonce, "n-0S6_WzA2Mj")
+		token.Set(jwt.ClaimAuthTime, time.Now().Add(-time.Minute).Unix())
+		token.Set(jwt.ClaimACR, "urn:mace:incommon:iap:silver")
+		token.Set(jwt.ClaimAccessTokenHash, atHash)
+		token.Set("email", "janedoe@example.com")
+		token.Set("email_verified", true)
+
+		if modify != nil {
+			modify(token)
+		}
+
+		tokenStr, err := signer.Sign(*token)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return tokenStr
+	}
+
+	validator := jwt.NewIDTokenValidator(issuer, clientID, jwt.IDTokenWithMaxAge(time.Hour))
+
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+
+		claims, err := validator.Verify(idToken(nil), *verifier,
+			jwt.IDTokenWithNonce("n-0S6_WzA2Mj"),
+			jwt.IDTokenWithAccessToken("access-token"),
+			jwt.IDTokenWithACRValues("urn:mace:incommon:iap:silver"),
+		)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if claims.Email != "janedoe@example.com" || !claims.EmailVerified {
+			t.Errorf("excepted verified email, got: %q %t", claims.Email, claims.EmailVerified)
+		}
+
+		if claims.Subject != "24400320" {
+			t.Errorf("excepted: %q, got: %q", "24400320", claims.Subject)
+		}
+
+		if claims.AuthTime.IsZero() {
+			t.Error("excepted auth time")
+		}
+	})
+
+	unset := func(claim string) func(token *jwt.Token) {
+		return func(token *jwt.Token) {
+			payload := token.GetPayload()
+			delete(payload, claim)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(token *jwt.Token)
+		opts   []jwt.IDTokenOption
+		err    error
+	}{
+		{"Wrong Issuer", func(token *jwt.Token) { token.SetIssuer("https://evil.example.com") }, nil, jwt.ErrInvalidIssuer},
+		{"Wrong Audience", func(token *jwt.Token) { token.SetAudience("other") }, nil, jwt.ErrInvalidAudience},
+		{"Multiple Audiences Without Azp", func(token *jwt.Token) { token.SetAudience(clientID, "other") }, nil, jwt.ErrInvalidAudience},
+		{"Wrong Azp", func(token *jwt.Token) { token.Set(jwt.ClaimAuthorizedParty, "other") }, nil, jwt.ErrInvalidAudience},
+		{"Missing Subject", unset(jwt.ClaimSubject), nil, jwt.ErrClaimNotFound},
+		{"Missing Issued At", unset(jwt.ClaimIssuedAt), nil, jwt.ErrClaimNotFound},
+		{"Expired", func(token *jwt.Token) { token.SetExpirationTime(time.Now().Add(-time.Minute)) }, nil, jwt.ErrTokenExpired},
+		{"Wrong Nonce", nil, []jwt.IDTokenOption{jwt.IDTokenWithNonce("other")}, jwt.ErrInvalidNonce},
+		{"Old Authentication", func(token *jwt.Token) {
+			token.Set(jwt.ClaimAuthTime, time.Now().Add(-2*time.Hour).Unix())
+		}, nil, jwt.ErrAuthTimeExpired},
+		{"Missing Auth Time", unset(jwt.ClaimAuthTime), nil, jwt.ErrClaimNotFound},
+		{"Wrong ACR", nil, []jwt.IDTokenOption{jwt.IDTokenWithACRValues("urn:mace:incommon:iap:gold")}, jwt.ErrInvalidACR},
+		{"Wrong Access Token", nil, []jwt.IDTokenOption{jwt.IDTokenWithAccessToken("other")}, jwt.ErrInvalidHash},
+		{"Wrong Code", func(token *jwt.Token) { token.Set(jwt.ClaimCodeHash, atHash) }, []jwt.IDTokenOption{jwt.IDTokenWithCode("code")}, jwt.ErrInvalidHash},
+		{"Missing Access Token Hash", unset(jwt.ClaimAccessTokenHash), []jwt.IDTokenOption{
+			jwt.IDTokenWithAccessToken("access-token"), jwt.IDTokenWithRequiredHashes(),
+		}, jwt.ErrClaimNotFound},
+		{"Missing Code Hash", nil, []jwt.IDTokenOption{jwt.IDTokenWithCode("code"), jwt.IDTokenWithRequiredHashes()}, jwt.ErrClaimNotFound},
+		{"Invalid Email Verified", func(token *jwt.Token) { token.Set("email_verified", "yes") }, nil, jwt.ErrInvalidClaimType},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := validator.Verify(idToken(tt.modify), *verifier, tt.opts...)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("excepted error %q, got %q", tt.err, err)
+			}
+		})
+	}
+
+	t.Run("String Email Verified", func(t *testing.T) {
+		t.Parallel()
+
+		tokenStr := idToken(func(token *jwt.Token) {
+			token.Set("email_verified", "true")
+			token.Set("phone_number_verified", "false")
+		})
+
+		claims, err := validator.Verify(tokenStr, *verifier)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if !claims.EmailVerified || claims.PhoneNumberVerified {
+			t.Errorf("excepted verified email only, got: %t %t", claims.EmailVerified, claims.PhoneNumberVerified)
+		}
+	})
+
+	t.Run("Optional Hashes", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := validator.Verify(idToken(unset(jwt.ClaimAccessTokenHash)), *verifier,
+			jwt.IDTokenWithAccessToken("access-token"),
+			jwt.IDTokenWithCode("code"),
+		)
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Required Hashes", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := validator.Verify(idToken(nil), *verifier,
+			jwt.IDTokenWithAccessToken("access-token"),
+			jwt.IDTokenWithRequiredHashes(),
+		)
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Wrong Algorithm", func(t *testing.T) {
+		t.Parallel()
+
+		tokenStr := idToken(nil)
+
+		other, err := jwt.NewVerifier(jwt.HS256, strongSecret)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_, err = validator.Verify(tokenStr, *other)
+		if !errors.Is(err, jwt.ErrUnsupportedAlgorithm) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrUnsupportedAlgorithm, err)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package jwt
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// PBES2 iteration counts.
+const (
+	// DefaultPBES2Count is the p2c header parameter of encryption unless EncryptWithPBES2Count is set.
+	DefaultPBES2Count = 310000
+	// MinPBES2Count is the minimum p2c accepted on encryption.
+	// https://datatracker.ietf.org/doc/html/rfc7518#section-4.8.1.2
+	MinPBES2Count = 1000
+	// DefaultMaxPBES2Count is the maximum p2c accepted on decryption unless DecryptWithMaxPBES2Count is set.
+	DefaultMaxPBES2Count = 1000000
+)
+
+const (
+	pbes2SaltSize    = 16
+	minPBES2SaltSize = 8
+)
+
+// EncryptWithPBES2Count sets the iteration count of PBES2 key derivation.
+func EncryptWithPBES2Count(count int) EncrypterOption {
+	return func(e *Encrypter) {
+		e.pbes2Count = count
+	}
+}
+
+// DecryptWithMaxPBES2Count sets the maximum iteration count of PBES2 key derivation.
+// Tokens with higher p2c are rejected before deriving the key, which bounds the cpu cost of decryption.
+func DecryptWithMaxPBES2Count(count int) DecrypterOption {
+	return func(d *Decrypter) {
+		d.maxPBES2Count = count
+	}
+}
+
+func isPBES2(alg KeyAlgorithm) bool {
+	return alg == PBES2HS256A128KW || alg == PBES2HS512A256KW
+}
+
+// encryptKeyPBES2 wraps a random content encryption key with a key derived from the password
+// and sets p2s and p2c header parameters.
+// https://datatracker.ietf.org/doc/html/rfc7518#section-4.8
+func (e Encrypter) encryptKeyPBES2(header *EncryptionHeader) (cek, encryptedKey []byte, err error) {
+	salt, err := generateSecret(pbes2SaltSize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error on generate salt: %w", err)
+	}
+
+	header.PBES2Salt = base64.RawURLEncoding.EncodeToString(salt)
+	header.PBES2Count = e.pbes2Count
+
+	cek, err = generateContentKey(e.enc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encryptedKey, err = wrapKey(derivePBES2Key(e.alg, e.key.([]byte), salt, e.pbes2Count), cek)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error on encrypt key: %w", err)
+	}
+
+	return cek, encryptedKey, nil
+}
+
+func (d Decrypter) decryptKeyPBES2(header *EncryptionHeader, encryptedKey []byte) ([]byte, error) {
+	if header.PBES2Count <= 0 || header.PBES2Count > d.maxPBES2Count {
+		return nil, ErrInvalidToken
+	}
+
+	salt, err := base64.RawURLEncoding.DecodeString(header.PBES2Salt)
+	if err != nil || len(salt) < minPBES2SaltSize {
+		return nil, ErrInvalidToken
+	}
+
+	cek, err := unwrapKey(derivePBES2Key(d.alg, d.key.([]byte), salt, header.PBES2Count), encryptedKey)
+
+	return contentKeyOrRandom(header.Encryption, cek, err)
+}
+
+// derivePBES2Key derives the key encryption key with PBKDF2 salted by the algorithm name.
+// https://datatracker.ietf.org/doc/html/rfc7518#section-4.8.1.1
+func derivePBES2Key(alg KeyAlgorithm, password, salt []byte, count int) []byte {
+	h := sha256.New
+	if alg == PBES2HS512A256KW {
+		h = sha512.New
+	}
+
+	input := make([]byte, 0, len(alg)+1+len(salt))
+	input = append(input, alg...)
+	input = append(input, 0)
+	input = append(input, salt...)
+
+	return pbkdf2.Key(password, input, count, keyWrapSize(alg), h)
+}
@@ -0,0 +1,171 @@
+package jwt_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestPBES2(t *testing.T) {
+	t.Parallel()
+
+	password := []byte("Thus from my lips, by yours, my sin is purged.")
+	plaintext := []byte("Live long and prosper.")
+
+	for _, alg := range []jwt.KeyAlgorithm{jwt.PBES2HS256A128KW, jwt.PBES2HS512A256KW} {
+		alg := alg
+
+		t.Run("Encrypt "+string(alg), func(t *testing.T) {
+			t.Parallel()
+
+			encrypter, err := jwt.NewEncrypter(alg, jwt.A128CBCHS256, password, jwt.EncryptWithPBES2Count(jwt.MinPBES2Count))
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			tokenStr, err := encrypter.Encrypt(plaintext)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			decrypter, err := jwt.NewDecrypter(alg, password)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			decrypted, err := decrypter.Decrypt(tokenStr)
+			if err != nil {
+				t.Error(err)
+			}
+
+			if string(decrypted) != string(plaintext) {
+				t.Errorf("excepted: %q, got: %q", plaintext, decrypted)
+			}
+
+			wrongDecrypter, err := jwt.NewDecrypter(alg, []byte("wrong password"))
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			_, err = wrongDecrypter.Decrypt(tokenStr)
+			if !errors.Is(err, jwt.ErrDecryptionFailed) {
+				t.Errorf("excepted error %q, got %q", jwt.ErrDecryptionFailed, err)
+			}
+		})
+	}
+
+	// https://datatracker.ietf.org/doc/html/rfc7517#appendix-C
+	t.Run("RFC 7517 Appendix C", func(t *testing.T) {
+		t.Parallel()
+
+		cek := []byte{
+			111, 27, 25, 52, 66, 29, 20, 78, 92, 176, 56, 240, 65, 208, 82, 112,
+			161, 131, 36, 55, 202, 236, 185, 172, 129, 23, 153, 194, 195, 48,
+			253, 182,
+		}
+
+		encryptedKey := []byte{
+			78, 186, 151, 59, 11, 141, 81, 240, 213, 245, 83, 211, 53, 188, 134,
+			188, 66, 125, 36, 200, 222, 124, 5, 103, 249, 52, 117, 184, 140, 81,
+			246, 158, 161, 177, 20, 33, 245, 57, 59, 4,
+		}
+
+		header := jwt.EncryptionHeader{
+			Algorithm:  jwt.PBES2HS256A128KW,
+			Encryption: jwt.A256GCM,
+			PBES2Salt:  "2WCTcJZ1Rvd_CJuJripQ1w",
+			PBES2Count: 4096,
+		}
+
+		decrypter, err := jwt.NewDecrypter(jwt.PBES2HS256A128KW, password)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decrypted, err := decrypter.Decrypt(encryptGCM(t, header, cek, encryptedKey, plaintext))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if string(decrypted) != string(plaintext) {
+			t.Errorf("excepted: %q, got: %q", plaintext, decrypted)
+		}
+	})
+
+	t.Run("Max Count", func(t *testing.T) {
+		t.Parallel()
+
+		salt := base64.RawURLEncoding.EncodeToString(make([]byte, 16))
+		cek := make([]byte, 16)
+
+		decrypter, err := jwt.NewDecrypter(jwt.PBES2HS256A128KW, password, jwt.DecryptWithMaxPBES2Count(2000))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, count := range []int{0, -1, 2001, jwt.DefaultMaxPBES2Count * 1000} {
+			header := jwt.EncryptionHeader{
+				Algorithm:  jwt.PBES2HS256A128KW,
+				Encryption: jwt.A128GCM,
+				PBES2Salt:  salt,
+				PBES2Count: count,
+			}
+
+			_, err = decrypter.Decrypt(encryptGCM(t, header, cek, make([]byte, 24), plaintext))
+			if !errors.Is(err, jwt.ErrInvalidToken) {
+				t.Errorf("p2c %d: excepted error %q, got %q", count, jwt.ErrInvalidToken, err)
+			}
+		}
+	})
+
+	t.Run("Short Salt", func(t *testing.T) {
+		t.Parallel()
+
+		decrypter, err := jwt.NewDecrypter(jwt.PBES2HS256A128KW, password)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		header := jwt.EncryptionHeader{
+			Algorithm:  jwt.PBES2HS256A128KW,
+			Encryption: jwt.A128GCM,
+			PBES2Salt:  base64.RawURLEncoding.EncodeToString(make([]byte, 4)),
+			PBES2Count: jwt.MinPBES2Count,
+		}
+
+		_, err = decrypter.Decrypt(encryptGCM(t, header, make([]byte, 16), make([]byte, 24), plaintext))
+		if !errors.Is(err, jwt.ErrInvalidToken) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrInvalidToken, err)
+		}
+	})
+
+	t.Run("Weak Count", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := jwt.NewEncrypter(jwt.PBES2HS256A128KW, jwt.A128GCM, password, jwt.EncryptWithPBES2Count(jwt.MinPBES2Count-1))
+		if !errors.Is(err, jwt.ErrWeakKey) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrWeakKey, err)
+		}
+	})
+
+	t.Run("Empty Password", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := jwt.NewEncrypter(jwt.PBES2HS256A128KW, jwt.A128GCM, []byte{})
+		if !errors.Is(err, jwt.ErrInvalidKey) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrInvalidKey, err)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package jwt
+
+import (
+	"sync"
+	"time"
+)
+
+// ReplayCache remembers used jwt ids to reject replayed tokens.
+type ReplayCache interface {
+	// Use marks the jwt id used until the expiration time and reports whether it was unused.
+	Use(jti string, exp time.Time) bool
+}
+
+// replayCacheSweepInterval is the interval of forgetting expired jwt ids of MemoryReplayCache.
+const replayCacheSweepInterval = time.Minute
+
+// MemoryReplayCache is ReplayCache of a single process.
+type MemoryReplayCache struct {
+	mu    sync.Mutex
+	used  map[string]time.Time
+	swept time.Time
+}
+
+// NewMemoryReplayCache returns empty in-memory replay cache.
+func NewMemoryReplayCache() *MemoryReplayCache {
+	return &MemoryReplayCache{
+		used:  make(map[string]time.Time),
+		swept: time.Now(),
+	}
+}
+
+// Use marks the jwt id used until the expiration time and reports whether it was unused.
+// Expired jwt ids are forgotten once a minute.
+func (c *MemoryReplayCache) Use(jti string, exp time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+
+	if now.Sub(c.swept) >= replayCacheSweepInterval {
+		c.sweep(now)
+	}
+
+	if used, ok := c.used[jti]; ok && !used.Before(now) {
+		return false
+	}
+
+	c.used[jti] = exp
+
+	return true
+}
+
+func (c *MemoryReplayCache) sweep(now time.Time) {
+	for k, v := range c.used {
+		if v.Before(now) {
+			delete(c.used, k)
+		}
+	}
+
+	c.swept = now
+}
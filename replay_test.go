@@ -0,0 +1,40 @@
+package jwt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestMemoryReplayCache(t *testing.T) {
+	t.Parallel()
+
+	cache := jwt.NewMemoryReplayCache()
+
+	t.Run("Replayed", func(t *testing.T) {
+		t.Parallel()
+
+		exp := time.Now().Add(time.Minute)
+
+		if !cache.Use("jti-1", exp) {
+			t.Error("excepted unused jwt id")
+		}
+
+		if cache.Use("jti-1", exp) {
+			t.Error("excepted used jwt id")
+		}
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		t.Parallel()
+
+		if !cache.Use("jti-2", time.Now().Add(-time.Second)) {
+			t.Error("excepted unused jwt id")
+		}
+
+		if !cache.Use("jti-2", time.Now().Add(time.Minute)) {
+			t.Error("excepted expired jwt id to be unused")
+		}
+	})
+}
@@ -0,0 +1,223 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TypeRequestObject is typ header parameter of request objects.
+// https://datatracker.ietf.org/doc/html/rfc9101#section-10.8
+const TypeRequestObject = "oauth-authz-req+jwt"
+
+// Request Object Parameters.
+const (
+	ParameterRequest    = "request"
+	ParameterRequestURI = "request_uri"
+	ParameterClientID   = "client_id"
+)
+
+// DefaultRequestObjectLifetime is the lifetime of signed request objects.
+const DefaultRequestObjectLifetime = 5 * time.Minute
+
+var ErrInvalidRequestObject = errors.New("invalid request object")
+
+// requestObjectClaims are the claims of request objects that are not authorization request parameters.
+var requestObjectClaims = map[string]bool{
+	ClaimIssuer:         true,
+	ClaimAudience:       true,
+	ClaimExpirationTime: true,
+	ClaimNotBefore:      true,
+	ClaimIssuedAt:       true,
+	ClaimJWTID:          true,
+}
+
+var requestObjectRequiredClaims = []string{ClaimIssuer, ClaimAudience, ClaimExpirationTime}
+
+// SignRequestObject signs request object of the authorization request parameters of the client
+// for the authorization server audience. Parameters are encoded as claims of their first value.
+// https://datatracker.ietf.org/doc/html/rfc9101#section-4
+func (s Signer) SignRequestObject(clientID, audience string, params url.Values) (string, error) {
+	jti, err := newJWTID()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+
+	token := New(s.alg)
+	token.header.Type = TypeRequestObject
+
+	for key := range params {
+		if key == ParameterRequest || key == ParameterRequestURI || requestObjectClaims[key] {
+			continue
+		}
+
+		token.Set(key, params.Get(key))
+	}
+
+	token.SetIssuer(clientID)
+	token.SetClientID(clientID)
+	token.SetAudience(audience)
+	token.SetJWTID(jti)
+	token.SetIssuedAt(now)
+	token.SetExpirationTime(now.Add(DefaultRequestObjectLifetime))
+
+	return s.Sign(*token)
+}
+
+// RequestObjectParameters returns the authorization request parameters passing the request object by value.
+// https://datatracker.ietf.org/doc/html/rfc9101#section-5.1
+func RequestObjectParameters(clientID, requestObject string) url.Values {
+	return url.Values{
+		ParameterClientID: {clientID},
+		ParameterRequest:  {requestObject},
+	}
+}
+
+// RequestObjectValidator validates request objects of clients for an authorization server.
+// https://datatracker.ietf.org/doc/html/rfc9101#section-6
+type RequestObjectValidator struct {
+	audience  string
+	decrypter *Decrypter
+}
+
+// RequestObjectOption configures request object validator.
+type RequestObjectOption func(*RequestObjectValidator)
+
+// RequestObjectWithDecrypter sets the decrypter of encrypted request objects.
+// Encrypted request objects are rejected without it.
+func RequestObjectWithDecrypter(decrypter Decrypter) RequestObjectOption {
+	return func(v *RequestObjectValidator) {
+		v.decrypter = &decrypter
+	}
+}
+
+// NewRequestObjectValidator returns request object validator of the authorization server audience, e.g. its issuer.
+func NewRequestObjectValidator(audience string, opts ...RequestObjectOption) *RequestObjectValidator {
+	v := RequestObjectValidator{
+		audience: audience,
+	}
+
+	for i := range opts {
+		opts[i](&v)
+	}
+
+	return &v
+}
+
+// AuthorizationRequest returns the authorization request parameters of the request object of the query
+// verified by the verifier of the client keys, e.g. KeySetVerifier of its registered json web key set.
+// Only request object parameters are used, other query parameters than client_id are ignored.
+// Queries without request object are returned unchanged.
+// https://datatracker.ietf.org/doc/html/rfc9101#section-6.3
+func (v RequestObjectValidator) AuthorizationRequest(query url.Values, verifier TokenVerifier) (url.Values, error) {
+	if query.Get(ParameterRequestURI) != "" {
+		return nil, fmt.Errorf("%w: %s is not supported", ErrInvalidRequestObject, ParameterRequestURI)
+	}
+
+	requestObject := query.Get(ParameterRequest)
+	if requestObject == "" {
+		return query, nil
+	}
+
+	token, err := v.Verify(requestObject, verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	clientID, err := token.GetClientID()
+	if err != nil || clientID != query.Get(ParameterClientID) {
+		return nil, fmt.Errorf("%w: %s mismatch", ErrInvalidRequestObject, ParameterClientID)
+	}
+
+	params := make(url.Values, len(token.payload))
+
+	for key, value := range token.payload {
+		if requestObjectClaims[key] {
+			continue
+		}
+
+		s, err := parameterValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRequestObject, err)
+		}
+
+		params.Set(key, s)
+	}
+
+	params.Set(ParameterClientID, clientID)
+
+	return params, nil
+}
+
+// Verify request object string, decrypting it first when it is encrypted, with the verifier of the client keys,
+// validate its claims and return it.
+func (v RequestObjectValidator) Verify(t string, verifier TokenVerifier) (*Token, error) {
+	if len(strings.Split(t, ".")) == encryptedTokenParts {
+		if v.decrypter == nil {
+			return nil, fmt.Errorf("%w: encrypted request objects are not accepted", ErrInvalidRequestObject)
+		}
+
+		signedToken, err := v.decrypter.DecryptNested(t)
+		if err != nil {
+			return nil, fmt.Errorf("invalid request object encryption: %w", err)
+		}
+
+		t = signedToken
+	}
+
+	token, err := verifier.VerifyType(t, TypeRequestObject, "", typeJWT)
+	if err != nil {
+		return nil, err
+	}
+
+	err = v.Validate(*token)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// Validate the claims of verified request object. Request objects must have iss, aud and exp claims.
+// https://datatracker.ietf.org/doc/html/rfc9101#section-4
+func (v RequestObjectValidator) Validate(token Token) error {
+	clientID, err := token.GetClientID()
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidRequestObject, err)
+	}
+
+	err = requireClaims(token, requestObjectRequiredClaims...)
+	if err != nil {
+		return err
+	}
+
+	if iss, err := token.GetIssuer(); err != nil || iss != clientID {
+		return ErrInvalidIssuer
+	}
+
+	if aud, err := token.GetAudience(); err != nil || !containsAll(aud, []string{v.audience}) {
+		return ErrInvalidAudience
+	}
+
+	return token.Validate()
+}
+
+// parameterValue returns the authorization request parameter of the claim value.
+// Values other than strings, such as claims parameter objects, are json encoded.
+func parameterValue(value interface{}) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("error on marshal parameter: %w", err)
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,185 @@
+package jwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestRequestObject(t *testing.T) {
+	t.Parallel()
+
+	const (
+		clientID = "s6BhdRkqt3"
+		issuer   = "https://server.example.com"
+	)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwk, err := jwt.NewJWK(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwk.KeyID = "client-1"
+	jwk.Algorithm = string(jwt.ES256)
+
+	signer, err := jwt.NewJWKSigner(*jwk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clientKeys := jwt.JWKSet{Keys: []jwt.JWK{*jwk}}.Public()
+	verifier := jwt.NewKeySetVerifier(clientKeys, []jwt.Algorithm{jwt.ES256})
+
+	encrypter, err := jwt.NewEncrypter(jwt.A256KW, jwt.A256GCM, strongSecret[:32])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decrypter, err := jwt.NewDecrypter(jwt.A256KW, strongSecret[:32])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validator := jwt.NewRequestObjectValidator(issuer, jwt.RequestObjectWithDecrypter(*decrypter))
+
+	requestObject, err := signer.SignRequestObject(clientID, issuer, url.Values{
+		"response_type": {"code"},
+		"redirect_uri":  {"https://client.example.org/cb"},
+		"scope":         {"openid profile"},
+		"state":         {"af0ifjsldkj"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encryptedRequestObject, err := encrypter.EncryptNested(requestObject)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, requestObject := range map[string]string{"Signed": requestObject, "Encrypted": encryptedRequestObject} {
+		requestObject := requestObject
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			query := jwt.RequestObjectParameters(clientID, requestObject)
+			query.Set("scope", "openid email")
+			query.Set("prompt", "login")
+
+			params, err := validator.AuthorizationRequest(query, verifier)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			excepted := url.Values{
+				"client_id":     {clientID},
+				"response_type": {"code"},
+				"redirect_uri":  {"https://client.example.org/cb"},
+				"scope":         {"openid profile"},
+				"state":         {"af0ifjsldkj"},
+			}
+
+			if params.Encode() != excepted.Encode() {
+				t.Errorf("excepted: %q, got: %q", excepted.Encode(), params.Encode())
+			}
+		})
+	}
+
+	t.Run("Without Request Object", func(t *testing.T) {
+		t.Parallel()
+
+		query := url.Values{"client_id": {clientID}, "scope": {"openid"}}
+
+		params, err := validator.AuthorizationRequest(query, verifier)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if params.Encode() != query.Encode() {
+			t.Errorf("excepted: %q, got: %q", query.Encode(), params.Encode())
+		}
+	})
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherSigner, err := jwt.NewCryptoSigner(jwt.ES256, otherKey, jwt.SignWithKeyID("client-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherSigned, err := otherSigner.SignRequestObject(clientID, issuer, url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherAudience, err := signer.SignRequestObject(clientID, "https://other.example.com", url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	withoutClaim := func(claim string) string {
+		token := jwt.New(jwt.ES256)
+		token.SetIssuer(clientID)
+		token.SetClientID(clientID)
+		token.SetAudience(issuer)
+		token.SetExpirationTime(time.Now().Add(time.Minute))
+
+		payload := token.GetPayload()
+		delete(payload, claim)
+
+		tokenStr, err := signer.Sign(*token)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return tokenStr
+	}
+
+	tests := []struct {
+		name      string
+		validator *jwt.RequestObjectValidator
+		query     url.Values
+		err       error
+	}{
+		{"Client ID", validator, jwt.RequestObjectParameters("other", requestObject), jwt.ErrInvalidRequestObject},
+		{"Request URI", validator, url.Values{"client_id": {clientID}, "request_uri": {"https://client.example.org/request.jwt"}}, jwt.ErrInvalidRequestObject},
+		{"Encrypted Without Decrypter", jwt.NewRequestObjectValidator(issuer), jwt.RequestObjectParameters(clientID, encryptedRequestObject), jwt.ErrInvalidRequestObject},
+		{"Other Key", validator, jwt.RequestObjectParameters(clientID, otherSigned), jwt.ErrInvalidTokenSignature},
+		{"Audience", validator, jwt.RequestObjectParameters(clientID, otherAudience), jwt.ErrInvalidAudience},
+		{"Missing Issuer", validator, jwt.RequestObjectParameters(clientID, withoutClaim(jwt.ClaimIssuer)), jwt.ErrClaimNotFound},
+		{"Missing Audience", validator, jwt.RequestObjectParameters(clientID, withoutClaim(jwt.ClaimAudience)), jwt.ErrClaimNotFound},
+		{"Missing Expiration Time", validator, jwt.RequestObjectParameters(clientID, withoutClaim(jwt.ClaimExpirationTime)), jwt.ErrClaimNotFound},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tt.validator.AuthorizationRequest(tt.query, verifier)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("excepted error %q, got %q", tt.err, err)
+			}
+		})
+	}
+}
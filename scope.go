@@ -0,0 +1,171 @@
+package jwt
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Authorization claims.
+const (
+	// https://datatracker.ietf.org/doc/html/rfc8693#section-4.2
+	ClaimScope = "scope"
+	// ClaimScopes is the array of scopes used by some authorization servers instead of ClaimScope.
+	ClaimScopes = "scp"
+	// https://datatracker.ietf.org/doc/html/rfc9068#section-2.2.3.1
+	ClaimRoles       = "roles"
+	ClaimPermissions = "permissions"
+)
+
+// SetScope sets the scopes as space-delimited scope claim.
+func (t *Token) SetScope(scopes ...string) {
+	t.Set(ClaimScope, strings.Join(scopes, " "))
+}
+
+// GetScopes returns the scopes of space-delimited scope claim or scp array claim.
+func (t Token) GetScopes() ([]string, error) {
+	value, exists := t.payload[ClaimScope]
+	if !exists {
+		return t.getStrings(ClaimScopes)
+	}
+
+	scope, ok := value.(string)
+	if !ok {
+		return nil, ErrInvalidClaimType
+	}
+
+	return strings.Fields(scope), nil
+}
+
+// HasScopes reports whether the token is granted all the scopes.
+func (t Token) HasScopes(scopes ...string) bool {
+	granted, err := t.GetScopes()
+	if err != nil {
+		return len(scopes) == 0
+	}
+
+	return containsAll(granted, scopes)
+}
+
+// GetRoles returns roles claim.
+func (t Token) GetRoles() ([]string, error) {
+	return t.getStrings(ClaimRoles)
+}
+
+// HasAnyRole reports whether the token has any of the roles.
+func (t Token) HasAnyRole(roles ...string) bool {
+	granted, _ := t.GetRoles()
+
+	return containsAny(granted, roles)
+}
+
+// GetPermissions returns permissions claim.
+func (t Token) GetPermissions() ([]string, error) {
+	return t.getStrings(ClaimPermissions)
+}
+
+// getStrings returns claim of a string or an array of strings.
+func (t Token) getStrings(key string) ([]string, error) {
+	value, exists := t.payload[key]
+	if !exists {
+		return nil, ErrClaimNotFound
+	}
+
+	switch v := value.(type) {
+	case string:
+		return []string{v}, nil
+	case []string:
+		return v, nil
+	case []interface{}:
+		res := make([]string, len(v))
+
+		for i := range v {
+			s, ok := v[i].(string)
+			if !ok {
+				return nil, ErrInvalidClaimType
+			}
+
+			res[i] = s
+		}
+
+		return res, nil
+	default:
+		return nil, ErrInvalidClaimType
+	}
+}
+
+func containsAll(values, required []string) bool {
+	set := make(map[string]struct{}, len(values))
+
+	for i := range values {
+		set[values[i]] = struct{}{}
+	}
+
+	for i := range required {
+		if _, ok := set[required[i]]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsAny(values, candidates []string) bool {
+	for i := range values {
+		for j := range candidates {
+			if values[i] == candidates[j] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// RequireScopes returns a wrapper of handlers that requires the token in the request context to have all the scopes.
+// https://datatracker.ietf.org/doc/html/rfc6750#section-3.1
+func (m BearerMiddleware) RequireScopes(scopes ...string) func(http.Handler) http.Handler {
+	scope := strings.Join(scopes, " ")
+
+	return m.require(func(token *Token) bool {
+		return token.HasScopes(scopes...)
+	}, "token requires scopes: "+scope, scope)
+}
+
+// RequireAnyRole returns a wrapper of handlers that requires the token in the request context to have any of the roles.
+func (m BearerMiddleware) RequireAnyRole(roles ...string) func(http.Handler) http.Handler {
+	return m.require(func(token *Token) bool {
+		return token.HasAnyRole(roles...)
+	}, "token requires any of roles: "+strings.Join(roles, " "), "")
+}
+
+// RequireClaim returns a wrapper of handlers that requires the claim of the token in the request context
+// to satisfy the predicate. Missing claims fail without calling the predicate.
+func (m BearerMiddleware) RequireClaim(key string, predicate func(value interface{}) bool) func(http.Handler) http.Handler {
+	return m.require(func(token *Token) bool {
+		value, err := token.Get(key)
+
+		return err == nil && predicate(value)
+	}, fmt.Sprintf("token claim %s is not sufficient", key), "")
+}
+
+func (m BearerMiddleware) require(authorize func(*Token) bool, description, scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := TokenFromContext(r.Context())
+			if !ok {
+				m.WriteError(w, "", "", "")
+
+				return
+			}
+
+			if !authorize(token) {
+				m.WriteError(w, BearerErrorInsufficientScope, description, scope)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package jwt_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestScopes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Scope", func(t *testing.T) {
+		t.Parallel()
+
+		token := jwt.New(jwt.HS256)
+		token.SetScope("read", "write")
+
+		scopes, err := token.GetScopes()
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if len(scopes) != 2 || scopes[0] != "read" || scopes[1] != "write" {
+			t.Errorf("excepted: %q, got: %q", []string{"read", "write"}, scopes)
+		}
+
+		if !token.HasScopes("write", "read") {
+			t.Error("excepted token to have scopes")
+		}
+
+		if token.HasScopes("read", "admin") {
+			t.Error("excepted token not to have scopes")
+		}
+	})
+
+	t.Run("Parsed Scp", func(t *testing.T) {
+		t.Parallel()
+
+		token := jwt.New(jwt.HS256)
+		token.Set(jwt.ClaimScopes, []string{"read", "write"})
+		token.Set(jwt.ClaimRoles, []string{"editor"})
+		token.Set(jwt.ClaimPermissions, "posts:publish")
+
+		tokenStr, err := jwt.Sign(*token, strongSecret)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		parsed, err := jwt.Parse(tokenStr)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if !parsed.HasScopes("read", "write") {
+			t.Error("excepted token to have scopes")
+		}
+
+		if !parsed.HasAnyRole("admin", "editor") {
+			t.Error("excepted token to have role")
+		}
+
+		permissions, err := parsed.GetPermissions()
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if len(permissions) != 1 || permissions[0] != "posts:publish" {
+			t.Errorf("excepted: %q, got: %q", []string{"posts:publish"}, permissions)
+		}
+	})
+
+	t.Run("Invalid Claim Type", func(t *testing.T) {
+		t.Parallel()
+
+		token := jwt.New(jwt.HS256)
+		token.Set(jwt.ClaimRoles, []interface{}{"admin", 1})
+
+		_, err := token.GetRoles()
+		if !errors.Is(err, jwt.ErrInvalidClaimType) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrInvalidClaimType, err)
+		}
+
+		_, err = jwt.New(jwt.HS256).GetScopes()
+		if !errors.Is(err, jwt.ErrClaimNotFound) {
+			t.Errorf("excepted error %q, got %q", jwt.ErrClaimNotFound, err)
+		}
+	})
+}
+
+func TestRequire(t *testing.T) {
+	t.Parallel()
+
+	verifier, err := jwt.NewVerifier(jwt.HS256, strongSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := jwt.NewBearerMiddleware(*verifier, jwt.BearerWithRealm("example"))
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	token := jwt.New(jwt.HS256)
+	token.SetScope("read")
+	token.Set(jwt.ClaimRoles, []string{"editor"})
+	token.Set("tenant", "acme")
+
+	isAcme := func(value interface{}) bool {
+		return value == "acme"
+	}
+
+	tests := []struct {
+		name      string
+		handler   http.Handler
+		token     *jwt.Token
+		status    int
+		challenge string
+	}{
+		{"Scopes", m.RequireScopes("read")(ok), token, http.StatusOK, ""},
+		{"Missing Scope", m.RequireScopes("read", "write")(ok), token, http.StatusForbidden,
+			`Bearer realm="example", error="insufficient_scope", error_description="token requires scopes: read write", scope="read write"`},
+		{"Role", m.RequireAnyRole("admin", "editor")(ok), token, http.StatusOK, ""},
+		{"Missing Role", m.RequireAnyRole("admin")(ok), token, http.StatusForbidden,
+			`Bearer realm="example", error="insufficient_scope", error_description="token requires any of roles: admin"`},
+		{"Claim", m.RequireClaim("tenant", isAcme)(ok), token, http.StatusOK, ""},
+		{"Missing Claim", m.RequireClaim("org", isAcme)(ok), token, http.StatusForbidden,
+			`Bearer realm="example", error="insufficient_scope", error_description="token claim org is not sufficient"`},
+		{"Unauthenticated", m.RequireScopes("read")(ok), nil, http.StatusUnauthorized, `Bearer realm="example"`},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.token != nil {
+				r = r.WithContext(jwt.ContextWithToken(r.Context(), tt.token))
+			}
+
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Errorf("excepted: %d, got: %d", tt.status, w.Code)
+			}
+
+			if challenge := w.Header().Get("WWW-Authenticate"); challenge != tt.challenge {
+				t.Errorf("excepted: %q, got: %q", tt.challenge, challenge)
+			}
+		})
+	}
+}
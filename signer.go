@@ -0,0 +1,303 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/asn1"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+)
+
+// MinRSAKeyBits is the minimum size of rsa keys accepted by signers and verifiers.
+const MinRSAKeyBits = 2048
+
+// ContextSigner is a crypto.Signer that accepts a context, e.g. a key of a remote key management service.
+// Like crypto.Signer, ECDSA signatures are returned ASN.1 DER encoded and for EdDSA the digest is the whole message.
+type ContextSigner interface {
+	Public() crypto.PublicKey
+	SignContext(ctx context.Context, digest []byte, opts crypto.SignerOpts) ([]byte, error)
+}
+
+// cryptoSigner adapts crypto.Signer to ContextSigner.
+type cryptoSigner struct {
+	crypto.Signer
+}
+
+func (s cryptoSigner) SignContext(ctx context.Context, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Sign(rand.Reader, digest, opts)
+}
+
+// Signer signs tokens with a key for an algorithm.
+type Signer struct {
+	alg      Algorithm
+	key      interface{}
+	weakKeys bool
+	keyID    string
+}
+
+// SignerOption configures signer.
+type SignerOption func(*Signer)
+
+// SignWithWeakKeys allows hmac secrets shorter than the hash output and rsa keys under MinRSAKeyBits.
+// It exists for interoperability with legacy systems only.
+func SignWithWeakKeys() SignerOption {
+	return func(s *Signer) {
+		s.weakKeys = true
+	}
+}
+
+// SignWithKeyID sets kid header parameter of signed tokens.
+func SignWithKeyID(kid string) SignerOption {
+	return func(s *Signer) {
+		s.keyID = kid
+	}
+}
+
+// NewSigner returns a signer of the algorithm.
+// The key is the secret for HS algorithms and the pem encoded private key otherwise.
+func NewSigner(alg Algorithm, key []byte, opts ...SignerOption) (*Signer, error) {
+	if !isSupported(alg) {
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	if isHMAC(alg) {
+		return newSigner(alg, key, opts)
+	}
+
+	private, err := parsePrivateKeyPEM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCryptoSigner(alg, private, opts...)
+}
+
+// NewCryptoSigner returns a signer of the algorithm that signs with crypto.Signer,
+// e.g. *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey or a key kept in a hardware security module.
+func NewCryptoSigner(alg Algorithm, signer crypto.Signer, opts ...SignerOption) (*Signer, error) {
+	return NewContextSigner(alg, cryptoSigner{signer}, opts...)
+}
+
+// NewContextSigner returns a signer of the algorithm that signs with ContextSigner.
+func NewContextSigner(alg Algorithm, signer ContextSigner, opts ...SignerOption) (*Signer, error) {
+	if isHMAC(alg) {
+		return nil, ErrInvalidKey
+	}
+
+	return newSigner(alg, signer, opts)
+}
+
+func newSigner(alg Algorithm, key interface{}, opts []SignerOption) (*Signer, error) {
+	s := Signer{
+		alg: alg,
+		key: key,
+	}
+
+	for i := range opts {
+		opts[i](&s)
+	}
+
+	var public interface{} = key
+	if signer, ok := key.(ContextSigner); ok {
+		public = signer.Public()
+	}
+
+	err := checkKey(alg, public, s.weakKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// Algorithm returns the algorithm of the signer.
+func (s Signer) Algorithm() Algorithm {
+	return s.alg
+}
+
+// Sign the token. The algorithm of the token header is set to the algorithm of the signer.
+func (s Signer) Sign(token Token) (string, error) {
+	return s.SignContext(context.Background(), token)
+}
+
+// SignContext signs the token like Sign and passes the context to ContextSigner.
+func (s Signer) SignContext(ctx context.Context, token Token) (string, error) {
+	token.header.Algorithm = s.alg
+
+	if s.keyID != "" {
+		token.header.KeyID = s.keyID
+	}
+
+	unsignedToken, err := encodeUnsignedToken(token)
+	if err != nil {
+		return "", err
+	}
+
+	signature, err := signWithKey(ctx, s.alg, s.key, []byte(unsignedToken))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s.%s", unsignedToken, base64.RawURLEncoding.EncodeToString(signature)), nil
+}
+
+func isSupported(alg Algorithm) bool {
+	_, err := hashByAlgorithm(alg)
+
+	return err == nil || alg == EdDSA
+}
+
+func isHMAC(alg Algorithm) bool {
+	return alg == HS256 || alg == HS384 || alg == HS512
+}
+
+func hashByAlgorithm(alg Algorithm) (crypto.Hash, error) {
+	switch alg {
+	case HS256, RS256, ES256, PS256:
+		return crypto.SHA256, nil
+	case HS384, RS384, ES384, PS384:
+		return crypto.SHA384, nil
+	case HS512, RS512, ES512, PS512:
+		return crypto.SHA512, nil
+	default:
+		return 0, ErrUnsupportedAlgorithm
+	}
+}
+
+func curveByAlgorithm(alg Algorithm) string {
+	switch alg {
+	case ES256:
+		return CurveP256
+	case ES384:
+		return CurveP384
+	default:
+		return CurveP521
+	}
+}
+
+// checkKey checks the key is of the type used by the algorithm and strong enough for it.
+// The key is a secret for HS algorithms and a public key otherwise.
+func checkKey(alg Algorithm, key interface{}, weakKeys bool) error {
+	switch alg {
+	case HS256, HS384, HS512:
+		secret, ok := key.([]byte)
+		if !ok {
+			return ErrInvalidKey
+		}
+
+		hash, _ := hashByAlgorithm(alg)
+
+		// https://datatracker.ietf.org/doc/html/rfc7518#section-3.2
+		if !weakKeys && len(secret) < hash.Size() {
+			return ErrWeakKey
+		}
+	case RS256, RS384, RS512, PS256, PS384, PS512:
+		public, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return ErrInvalidKey
+		}
+
+		if !weakKeys && public.N.BitLen() < MinRSAKeyBits {
+			return ErrWeakKey
+		}
+	case ES256, ES384, ES512:
+		public, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return ErrInvalidKey
+		}
+
+		if crv, _ := curveName(public.Curve); crv != curveByAlgorithm(alg) {
+			return ErrInvalidKey
+		}
+	case EdDSA:
+		if _, ok := key.(ed25519.PublicKey); !ok {
+			return ErrInvalidKey
+		}
+	default:
+		return ErrUnsupportedAlgorithm
+	}
+
+	return nil
+}
+
+func signWithKey(ctx context.Context, alg Algorithm, key interface{}, unsignedToken []byte) ([]byte, error) {
+	if alg == EdDSA {
+		// https://datatracker.ietf.org/doc/html/rfc8032#section-5.1.6
+		return signWithContextSigner(ctx, key.(ContextSigner), unsignedToken, crypto.Hash(0))
+	}
+
+	hash, err := hashByAlgorithm(alg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch alg {
+	case HS256, HS384, HS512:
+		mac := hmac.New(hash.New, key.([]byte))
+		_, _ = mac.Write(unsignedToken)
+
+		return mac.Sum(nil), nil
+	case RS256, RS384, RS512:
+		return signWithContextSigner(ctx, key.(ContextSigner), digest(hash, unsignedToken), hash)
+	case PS256, PS384, PS512:
+		opts := rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: hash}
+
+		return signWithContextSigner(ctx, key.(ContextSigner), digest(hash, unsignedToken), &opts)
+	case ES256, ES384, ES512:
+		signer := key.(ContextSigner)
+
+		der, err := signWithContextSigner(ctx, signer, digest(hash, unsignedToken), hash)
+		if err != nil {
+			return nil, err
+		}
+
+		return ecdsaSignatureFromDER(der, curveSize(signer.Public().(*ecdsa.PublicKey).Curve))
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+}
+
+func signWithContextSigner(
+	ctx context.Context, signer ContextSigner, digest []byte, opts crypto.SignerOpts,
+) ([]byte, error) {
+	signature, err := signer.SignContext(ctx, digest, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error on sign token: %w", err)
+	}
+
+	return signature, nil
+}
+
+// ecdsaSignatureFromDER converts ASN.1 DER signature of crypto.Signer to R || S.
+// https://datatracker.ietf.org/doc/html/rfc7518#section-3.4
+func ecdsaSignatureFromDER(der []byte, size int) ([]byte, error) {
+	var sig struct {
+		R, S *big.Int
+	}
+
+	rest, err := asn1.Unmarshal(der, &sig)
+	if err != nil || len(rest) != 0 {
+		return nil, fmt.Errorf("error on sign token: %w", ErrInvalidTokenSignature)
+	}
+
+	if sig.R.BitLen() > size*bitsPerByte || sig.S.BitLen() > size*bitsPerByte {
+		return nil, fmt.Errorf("error on sign token: %w", ErrInvalidTokenSignature)
+	}
+
+	b := make([]byte, 2*size)
+	sig.R.FillBytes(b[:size])
+	sig.S.FillBytes(b[size:])
+
+	return b, nil
+}
@@ -0,0 +1,361 @@
+package jwt_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+var strongSecret = []byte("0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef")
+
+func weakRSAKey(t *testing.T) (privatePEM, publicPEM []byte) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privatePEM = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	publicPEM = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b})
+
+	return privatePEM, publicPEM
+}
+
+func TestNewSigner(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Invalid algorithm", func(t *testing.T) {
+		t.Parallel()
+
+		signer, err := jwt.NewSigner("foo", secret)
+		if !errors.Is(err, jwt.ErrUnsupportedAlgorithm) {
+			t.Error(err)
+		}
+
+		if signer != nil {
+			t.Errorf("excepted nil but got '%T'", signer)
+		}
+	})
+
+	t.Run("Weak HS256 secret", func(t *testing.T) {
+		t.Parallel()
+
+		signer, err := jwt.NewSigner(jwt.HS256, secret)
+		if !errors.Is(err, jwt.ErrWeakKey) {
+			t.Error(err)
+		}
+
+		if signer != nil {
+			t.Errorf("excepted nil but got '%T'", signer)
+		}
+	})
+
+	t.Run("Weak HS512 secret", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := jwt.NewSigner(jwt.HS512, strongSecret[:48])
+		if !errors.Is(err, jwt.ErrWeakKey) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Weak HS256 secret allowed", func(t *testing.T) {
+		t.Parallel()
+
+		excepted := "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.e30.HUfJqC1q8JUPKD4jj8PZAYppSrQRL8tJHTljdcTfFCQ"
+
+		signer, err := jwt.NewSigner(jwt.HS256, secret, jwt.SignWithWeakKeys())
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		tokenStr, err := signer.Sign(*jwt.New(jwt.HS256))
+		if err != nil {
+			t.Error(err)
+		}
+
+		if tokenStr != excepted {
+			t.Errorf("excepted: %q, got: %q", excepted, tokenStr)
+		}
+	})
+
+	t.Run("Weak RSA key", func(t *testing.T) {
+		t.Parallel()
+
+		privatePEM, _ := weakRSAKey(t)
+
+		_, err := jwt.NewSigner(jwt.RS256, privatePEM)
+		if !errors.Is(err, jwt.ErrWeakKey) {
+			t.Error(err)
+		}
+
+		_, err = jwt.NewSigner(jwt.RS256, privatePEM, jwt.SignWithWeakKeys())
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Invalid pem", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := jwt.NewSigner(jwt.RS256, []byte(""))
+		if !errors.Is(err, jwt.ErrInvalidPem) {
+			t.Error(err)
+		}
+	})
+}
+
+func TestSigner_Sign(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Sign RS256", func(t *testing.T) {
+		t.Parallel()
+
+		excepted := "eyJhbGciOiJSUzI1NiIsInR5cCI6IkpXVCJ9.e30.B1Q-D-h1NW2DbAippP-l6H9YHX22HnZZl15PHO7CC6K0ZcbnOr0IjnyOZFeLUB-02z3ausdsWn7FlZj_juqRfeIlpP7ysJwp0kPGpGJqXE-YlrOrR_KRcs7EjIb53ICV76WPP149h_qu57hIYAlJwSZgy77wkXKoq73psXI0ZAl_0YC7kgGyz_aE7Wwk3-BLcEqhKyC6yG4RoBzqHJgZXEShYUkCWjdwa5O3ogQ0-dMtjp3jXG-l42RaOJqqNYNegBstQWL874hfYQcVxuWdTeBtqTqXsGp2sH60NEd5h6Z-3Ef0nw0bbCTK0ustCHZn5RN4HHvCiazriqK4CdkPrw"
+
+		signer, err := jwt.NewSigner(jwt.RS256, private)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		tokenStr, err := signer.Sign(*jwt.New(jwt.RS256))
+		if err != nil {
+			t.Error(err)
+		}
+
+		if tokenStr != excepted {
+			t.Errorf("excepted: %q, got: %q", excepted, tokenStr)
+		}
+	})
+
+	t.Run("Sign overrides header algorithm", func(t *testing.T) {
+		t.Parallel()
+
+		signer, err := jwt.NewSigner(jwt.HS512, strongSecret)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		tokenStr, err := signer.Sign(*jwt.New(jwt.HS256))
+		if err != nil {
+			t.Error(err)
+		}
+
+		token, err := jwt.Parse(tokenStr)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		if alg := token.GetHeader().Algorithm; alg != jwt.HS512 {
+			t.Errorf("excepted: %q, got: %q", jwt.HS512, alg)
+		}
+	})
+}
+
+// fakeSigner is an in-process stand-in for keys kept in a hardware security module or key management service.
+type fakeSigner struct {
+	key   crypto.Signer
+	calls int32
+}
+
+func (s *fakeSigner) Public() crypto.PublicKey {
+	return s.key.Public()
+}
+
+func (s *fakeSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	atomic.AddInt32(&s.calls, 1)
+
+	return s.key.Sign(rand, digest, opts)
+}
+
+func (s *fakeSigner) SignContext(ctx context.Context, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+		return s.Sign(rand.Reader, digest, opts)
+	}
+}
+
+func TestNewCryptoSigner(t *testing.T) {
+	t.Parallel()
+
+	for _, alg := range []jwt.Algorithm{
+		jwt.RS256, jwt.RS384, jwt.RS512,
+		jwt.PS256, jwt.PS384, jwt.PS512,
+		jwt.ES256, jwt.ES384, jwt.ES512,
+		jwt.EdDSA,
+	} {
+		alg := alg
+
+		t.Run("Sign "+string(alg), func(t *testing.T) {
+			t.Parallel()
+
+			key, err := jwt.GenerateKey(alg)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			fake := fakeSigner{key: key.Raw().(crypto.Signer)}
+
+			signer, err := jwt.NewCryptoSigner(alg, &fake)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			tokenStr, err := signer.Sign(*jwt.New(alg))
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			if calls := atomic.LoadInt32(&fake.calls); calls != 1 {
+				t.Errorf("excepted: %d, got: %d", 1, calls)
+			}
+
+			verifier, err := jwt.NewPublicKeyVerifier(alg, key.Public())
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			err = verifier.Verify(tokenStr)
+			if err != nil {
+				t.Error(err)
+			}
+
+			publicPEM, err := key.PublicKeyPEM()
+			if err != nil {
+				t.Error(err)
+			}
+
+			err = jwt.Verify(tokenStr, publicPEM)
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	t.Run("Key does not match algorithm", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := jwt.GenerateKey(jwt.ES256)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_, err = jwt.NewCryptoSigner(jwt.ES384, key.Raw().(crypto.Signer))
+		if !errors.Is(err, jwt.ErrInvalidKey) {
+			t.Error(err)
+		}
+
+		_, err = jwt.NewCryptoSigner(jwt.RS256, key.Raw().(crypto.Signer))
+		if !errors.Is(err, jwt.ErrInvalidKey) {
+			t.Error(err)
+		}
+
+		_, err = jwt.NewCryptoSigner(jwt.HS256, key.Raw().(crypto.Signer))
+		if !errors.Is(err, jwt.ErrInvalidKey) {
+			t.Error(err)
+		}
+	})
+}
+
+func TestSigner_SignContext(t *testing.T) {
+	t.Parallel()
+
+	key, err := jwt.GenerateKey(jwt.ES256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Remote signer", func(t *testing.T) {
+		t.Parallel()
+
+		fake := fakeSigner{key: key.Raw().(crypto.Signer)}
+
+		signer, err := jwt.NewContextSigner(jwt.ES256, &fake)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		tokenStr, err := signer.SignContext(context.Background(), *jwt.New(jwt.ES256))
+		if err != nil {
+			t.Error(err)
+		}
+
+		verifier, err := jwt.NewPublicKeyVerifier(jwt.ES256, key.Public())
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		err = verifier.Verify(tokenStr)
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Canceled context", func(t *testing.T) {
+		t.Parallel()
+
+		fake := fakeSigner{key: key.Raw().(crypto.Signer)}
+
+		signer, err := jwt.NewContextSigner(jwt.ES256, &fake)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		tokenStr, err := signer.SignContext(ctx, *jwt.New(jwt.ES256))
+		if !errors.Is(err, context.Canceled) {
+			t.Error(err)
+		}
+
+		if tokenStr != "" {
+			t.Errorf("excepted: %q, got: %q", "", tokenStr)
+		}
+
+		if calls := atomic.LoadInt32(&fake.calls); calls != 0 {
+			t.Errorf("excepted: %d, got: %d", 0, calls)
+		}
+	})
+}
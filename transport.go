@@ -0,0 +1,187 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Token lifetimes of transport.
+const (
+	// DefaultExpiryDelta is how long before expiration cached tokens are refreshed unless TransportWithExpiryDelta is set.
+	DefaultExpiryDelta = 10 * time.Second
+	// DefaultTokenLifetime is the lifetime of tokens signed by SignedTokenSource unless set.
+	DefaultTokenLifetime = 5 * time.Minute
+)
+
+// TokenSource returns token strings for outbound requests.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc adapts a function to TokenSource.
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+// Token calls f(ctx).
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// SignedTokenSource returns a token source that signs a copy of the template with the signer on each call.
+// The copy is issued now, expires after the lifetime and has a random jwt id.
+func SignedTokenSource(signer Signer, template Token, lifetime time.Duration) TokenSource {
+	if lifetime <= 0 {
+		lifetime = DefaultTokenLifetime
+	}
+
+	return TokenSourceFunc(func(ctx context.Context) (string, error) {
+		token := template.clone()
+
+		jti, err := newJWTID()
+		if err != nil {
+			return "", err
+		}
+
+		now := time.Now()
+		token.SetIssuedAt(now)
+		token.SetExpirationTime(now.Add(lifetime))
+		token.SetJWTID(jti)
+
+		return signer.SignContext(ctx, token)
+	})
+}
+
+func (t Token) clone() Token {
+	payload := make(Payload, len(t.payload))
+	for k, v := range t.payload {
+		payload[k] = v
+	}
+
+	t.payload = payload
+
+	return t
+}
+
+// Transport is http.RoundTripper that authorizes requests with bearer tokens of the source.
+// Tokens are cached until shortly before their expiration and refreshed by one request at a time.
+type Transport struct {
+	source      TokenSource
+	base        http.RoundTripper
+	expiryDelta time.Duration
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// TransportOption configures transport.
+type TransportOption func(*Transport)
+
+// TransportWithBase sets the round tripper that sends the requests. It is http.DefaultTransport by default.
+func TransportWithBase(base http.RoundTripper) TransportOption {
+	return func(t *Transport) {
+		t.base = base
+	}
+}
+
+// TransportWithExpiryDelta sets how long before expiration cached tokens are refreshed.
+func TransportWithExpiryDelta(delta time.Duration) TransportOption {
+	return func(t *Transport) {
+		t.expiryDelta = delta
+	}
+}
+
+// NewTransport returns transport that gets tokens from the source.
+func NewTransport(source TokenSource, opts ...TransportOption) *Transport {
+	t := Transport{
+		source:      source,
+		base:        http.DefaultTransport,
+		expiryDelta: DefaultExpiryDelta,
+	}
+
+	for i := range opts {
+		opts[i](&t)
+	}
+
+	return &t
+}
+
+// RoundTrip sends the request with authorization header.
+// Requests answered with 401 are sent once more with a new token if their body can be sent again.
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	token, err := t.getToken(r.Context(), "")
+	if err != nil {
+		// round trippers must close the request body, including on errors
+		if r.Body != nil {
+			_ = r.Body.Close()
+		}
+
+		return nil, err
+	}
+
+	res, err := t.base.RoundTrip(authorizedRequest(r, token))
+	if err != nil || res.StatusCode != http.StatusUnauthorized {
+		return res, err
+	}
+
+	if r.Body != nil && r.Body != http.NoBody && r.GetBody == nil {
+		return res, nil
+	}
+
+	token, err = t.getToken(r.Context(), token)
+	if err != nil {
+		return res, nil
+	}
+
+	retry := authorizedRequest(r, token)
+
+	if r.GetBody != nil {
+		retry.Body, err = r.GetBody()
+		if err != nil {
+			return res, nil
+		}
+	}
+
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+	_ = res.Body.Close()
+
+	return t.base.RoundTrip(retry)
+}
+
+// getToken returns the cached token unless it is about to expire or it is the rejected token.
+func (t *Transport) getToken(ctx context.Context, rejected string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token != "" && t.token != rejected && time.Now().Add(t.expiryDelta).Before(t.expiry) {
+		return t.token, nil
+	}
+
+	token, err := t.source.Token(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error on get token: %w", err)
+	}
+
+	t.token, t.expiry = token, time.Time{}
+
+	// tokens without expiration time or of other formats are not cached
+	if parsed, err := Parse(token); err == nil {
+		if exp, err := parsed.GetExpirationTime(); err == nil {
+			t.expiry = exp
+		}
+	}
+
+	return token, nil
+}
+
+// authorizedRequest returns a copy of the request with authorization header of the token.
+func authorizedRequest(r *http.Request, token string) *http.Request {
+	req := r.Clone(r.Context())
+	req.Header.Set("Authorization", bearerScheme+" "+token)
+
+	return req
+}
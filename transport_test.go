@@ -0,0 +1,241 @@
+package jwt_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestTransport(t *testing.T) {
+	t.Parallel()
+
+	signer, err := jwt.NewSigner(jwt.HS256, strongSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := jwt.NewVerifier(jwt.HS256, strongSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := jwt.NewBearerMiddleware(*verifier)
+
+	server := httptest.NewServer(m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, _ := jwt.TokenFromContext(r.Context())
+		sub, _ := token.GetSubject()
+
+		body, _ := ioutil.ReadAll(r.Body)
+		_, _ = fmt.Fprintf(w, "%s:%s", sub, body)
+	})))
+	t.Cleanup(server.Close)
+
+	template := jwt.New(jwt.HS256)
+	template.SetSubject("service-a")
+
+	t.Run("Signed Token Source", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int32
+
+		source := jwt.SignedTokenSource(*signer, *template, time.Minute)
+		counting := jwt.TokenSourceFunc(func(ctx context.Context) (string, error) {
+			atomic.AddInt32(&calls, 1)
+
+			return source.Token(ctx)
+		})
+
+		client := http.Client{Transport: jwt.NewTransport(counting)}
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				res, err := client.Get(server.URL)
+				if err != nil {
+					t.Error(err)
+
+					return
+				}
+
+				defer res.Body.Close()
+
+				body, _ := ioutil.ReadAll(res.Body)
+				if string(body) != "service-a:" {
+					t.Errorf("excepted: %q, got: %q", "service-a:", body)
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		if calls != 1 {
+			t.Errorf("excepted: %d, got: %d", 1, calls)
+		}
+
+		if _, err := template.GetExpirationTime(); err == nil {
+			t.Error("excepted template not to be changed")
+		}
+	})
+
+	t.Run("Refresh Before Expiration", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int32
+
+		source := jwt.SignedTokenSource(*signer, *template, 5*time.Second)
+		counting := jwt.TokenSourceFunc(func(ctx context.Context) (string, error) {
+			atomic.AddInt32(&calls, 1)
+
+			return source.Token(ctx)
+		})
+
+		client := http.Client{Transport: jwt.NewTransport(counting, jwt.TransportWithExpiryDelta(10*time.Second))}
+
+		for i := 0; i < 3; i++ {
+			res, err := client.Get(server.URL)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			_ = res.Body.Close()
+		}
+
+		if calls != 3 {
+			t.Errorf("excepted: %d, got: %d", 3, calls)
+		}
+	})
+
+	t.Run("Retry On Unauthorized", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int32
+
+		valid, err := jwt.SignedTokenSource(*signer, *template, time.Minute).Token(context.Background())
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		source := jwt.TokenSourceFunc(func(ctx context.Context) (string, error) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				return "revoked", nil
+			}
+
+			return valid, nil
+		})
+
+		client := http.Client{Transport: jwt.NewTransport(source)}
+
+		res, err := client.Post(server.URL, "text/plain", strings.NewReader("hello"))
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			t.Errorf("excepted: %d, got: %d", http.StatusOK, res.StatusCode)
+		}
+
+		body, _ := ioutil.ReadAll(res.Body)
+		if string(body) != "service-a:hello" {
+			t.Errorf("excepted: %q, got: %q", "service-a:hello", body)
+		}
+
+		if calls != 2 {
+			t.Errorf("excepted: %d, got: %d", 2, calls)
+		}
+	})
+
+	t.Run("Retry Once", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int32
+
+		source := jwt.TokenSourceFunc(func(ctx context.Context) (string, error) {
+			atomic.AddInt32(&calls, 1)
+
+			return "revoked", nil
+		})
+
+		client := http.Client{Transport: jwt.NewTransport(source)}
+
+		res, err := client.Get(server.URL)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_ = res.Body.Close()
+
+		if res.StatusCode != http.StatusUnauthorized {
+			t.Errorf("excepted: %d, got: %d", http.StatusUnauthorized, res.StatusCode)
+		}
+
+		if calls != 2 {
+			t.Errorf("excepted: %d, got: %d", 2, calls)
+		}
+	})
+
+	t.Run("Token Source Error", func(t *testing.T) {
+		t.Parallel()
+
+		errNoToken := errors.New("no token")
+
+		source := jwt.TokenSourceFunc(func(ctx context.Context) (string, error) {
+			return "", errNoToken
+		})
+
+		body := &trackedBody{Reader: strings.NewReader("payload")}
+
+		r, err := http.NewRequest(http.MethodPost, server.URL, body)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		_, err = jwt.NewTransport(source).RoundTrip(r)
+		if !errors.Is(err, errNoToken) {
+			t.Errorf("excepted error %q, got %q", errNoToken, err)
+		}
+
+		if !body.closed {
+			t.Error("excepted request body to be closed")
+		}
+	})
+}
+
+// trackedBody is request body that records whether it is closed.
+type trackedBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *trackedBody) Close() error {
+	b.closed = true
+
+	return nil
+}
@@ -0,0 +1,231 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// TokenVerifier verifies token strings of media types and returns the parsed tokens.
+// It is implemented by Verifier and KeySetVerifier.
+type TokenVerifier interface {
+	VerifyType(t string, types ...string) (*Token, error)
+}
+
+// Verifier verifies token signatures with a key for an algorithm.
+type Verifier struct {
+	alg      Algorithm
+	key      interface{}
+	weakKeys bool
+}
+
+// VerifierOption configures verifier.
+type VerifierOption func(*Verifier)
+
+// VerifyWithWeakKeys allows hmac secrets shorter than the hash output and rsa keys under MinRSAKeyBits.
+// It exists for interoperability with legacy systems only.
+func VerifyWithWeakKeys() VerifierOption {
+	return func(v *Verifier) {
+		v.weakKeys = true
+	}
+}
+
+// NewVerifier returns a verifier of the algorithm.
+// The key is the secret for HS algorithms and the pem encoded public key otherwise.
+func NewVerifier(alg Algorithm, key []byte, opts ...VerifierOption) (*Verifier, error) {
+	if !isSupported(alg) {
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	if isHMAC(alg) {
+		return newVerifier(alg, key, opts)
+	}
+
+	public, err := parsePublicKeyPEM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPublicKeyVerifier(alg, public, opts...)
+}
+
+// NewPublicKeyVerifier returns a verifier of the algorithm with *rsa.PublicKey, *ecdsa.PublicKey or ed25519.PublicKey.
+func NewPublicKeyVerifier(alg Algorithm, public crypto.PublicKey, opts ...VerifierOption) (*Verifier, error) {
+	if isHMAC(alg) {
+		return nil, ErrInvalidKey
+	}
+
+	return newVerifier(alg, public, opts)
+}
+
+func newVerifier(alg Algorithm, key interface{}, opts []VerifierOption) (*Verifier, error) {
+	v := Verifier{
+		alg: alg,
+		key: key,
+	}
+
+	for i := range opts {
+		opts[i](&v)
+	}
+
+	err := checkKey(alg, key, v.weakKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// Algorithm returns the algorithm of the verifier.
+func (v Verifier) Algorithm() Algorithm {
+	return v.alg
+}
+
+// Verify token string. Tokens signed with any other algorithm than the verifier's are rejected.
+func (v Verifier) Verify(t string) error {
+	arr := strings.Split(t, ".")
+	if len(arr) != tokenParts {
+		return ErrInvalidToken
+	}
+
+	header, err := decodeHeader(arr[0])
+	if err != nil {
+		return err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(arr[2])
+	if err != nil {
+		return fmt.Errorf("invalid token signature encoding: %w", err)
+	}
+
+	return v.verifySignature(header, []byte(fmt.Sprintf("%s.%s", arr[0], arr[1])), signature)
+}
+
+// VerifyType verifies token string of any of the media types, e.g. at+jwt, and returns the parsed token.
+// Types are compared case-insensitively and with or without application/ prefix.
+// An empty type accepts tokens without typ header parameter.
+// https://datatracker.ietf.org/doc/html/rfc8725#section-3.11
+func (v Verifier) VerifyType(t string, types ...string) (*Token, error) {
+	arr := strings.Split(t, ".")
+	if len(arr) != tokenParts {
+		return nil, ErrInvalidToken
+	}
+
+	header, err := decodeJOSEHeader(arr[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if !isType(header.Type, types...) {
+		return nil, ErrUnsupportedTokenType
+	}
+
+	if !header.isPayloadEncoded() {
+		return nil, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(arr[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token signature encoding: %w", err)
+	}
+
+	err = v.verifySignature(header, []byte(fmt.Sprintf("%s.%s", arr[0], arr[1])), signature)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(t)
+}
+
+// https://datatracker.ietf.org/doc/html/rfc7515#section-4.1.9
+func isType(typ string, types ...string) bool {
+	const prefix = "application/"
+
+	if len(typ) > len(prefix) && strings.EqualFold(typ[:len(prefix)], prefix) {
+		typ = typ[len(prefix):]
+	}
+
+	for i := range types {
+		if strings.EqualFold(typ, types[i]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (v Verifier) verifySignature(header *Header, unsignedToken, signature []byte) error {
+	if header.Algorithm != v.alg {
+		return ErrUnsupportedAlgorithm
+	}
+
+	return verifyWithKey(v.alg, v.key, unsignedToken, signature)
+}
+
+func verifyWithKey(alg Algorithm, key interface{}, unsignedToken, signature []byte) error {
+	if alg == EdDSA {
+		if !ed25519.Verify(key.(ed25519.PublicKey), unsignedToken, signature) {
+			return ErrInvalidTokenSignature
+		}
+
+		return nil
+	}
+
+	hash, err := hashByAlgorithm(alg)
+	if err != nil {
+		return err
+	}
+
+	switch alg {
+	case HS256, HS384, HS512:
+		mac := hmac.New(hash.New, key.([]byte))
+		_, _ = mac.Write(unsignedToken)
+
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return ErrInvalidTokenSignature
+		}
+	case RS256, RS384, RS512:
+		err = rsa.VerifyPKCS1v15(key.(*rsa.PublicKey), hash, digest(hash, unsignedToken), signature)
+		if err != nil {
+			return ErrInvalidTokenSignature
+		}
+	case PS256, PS384, PS512:
+		opts := rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: hash}
+
+		err = rsa.VerifyPSS(key.(*rsa.PublicKey), hash, digest(hash, unsignedToken), signature, &opts)
+		if err != nil {
+			return ErrInvalidTokenSignature
+		}
+	case ES256, ES384, ES512:
+		public := key.(*ecdsa.PublicKey)
+
+		size := curveSize(public.Curve)
+		if len(signature) != 2*size {
+			return ErrInvalidTokenSignature
+		}
+
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+
+		if !ecdsa.Verify(public, digest(hash, unsignedToken), r, s) {
+			return ErrInvalidTokenSignature
+		}
+	default:
+		return ErrUnsupportedAlgorithm
+	}
+
+	return nil
+}
+
+func digest(hash crypto.Hash, b []byte) []byte {
+	h := hash.New()
+	_, _ = h.Write(b)
+
+	return h.Sum(nil)
+}
@@ -0,0 +1,142 @@
+package jwt_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nasermirzaei89/jwt"
+)
+
+func TestNewVerifier(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Invalid algorithm", func(t *testing.T) {
+		t.Parallel()
+
+		verifier, err := jwt.NewVerifier("foo", secret)
+		if !errors.Is(err, jwt.ErrUnsupportedAlgorithm) {
+			t.Error(err)
+		}
+
+		if verifier != nil {
+			t.Errorf("excepted nil but got '%T'", verifier)
+		}
+	})
+
+	t.Run("Weak HS256 secret", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := jwt.NewVerifier(jwt.HS256, secret)
+		if !errors.Is(err, jwt.ErrWeakKey) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Weak RSA key", func(t *testing.T) {
+		t.Parallel()
+
+		_, publicPEM := weakRSAKey(t)
+
+		_, err := jwt.NewVerifier(jwt.RS256, publicPEM)
+		if !errors.Is(err, jwt.ErrWeakKey) {
+			t.Error(err)
+		}
+
+		_, err = jwt.NewVerifier(jwt.RS256, publicPEM, jwt.VerifyWithWeakKeys())
+		if err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Verify HS256 with weak secret allowed", func(t *testing.T) {
+		t.Parallel()
+
+		tokenStr := "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.e30.HUfJqC1q8JUPKD4jj8PZAYppSrQRL8tJHTljdcTfFCQ"
+
+		verifier, err := jwt.NewVerifier(jwt.HS256, secret, jwt.VerifyWithWeakKeys())
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		err = verifier.Verify(tokenStr)
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Verify RS256", func(t *testing.T) {
+		t.Parallel()
+
+		tokenStr := "eyJhbGciOiJSUzI1NiIsInR5cCI6IkpXVCJ9.e30.B1Q-D-h1NW2DbAippP-l6H9YHX22HnZZl15PHO7CC6K0ZcbnOr0IjnyOZFeLUB-02z3ausdsWn7FlZj_juqRfeIlpP7ysJwp0kPGpGJqXE-YlrOrR_KRcs7EjIb53ICV76WPP149h_qu57hIYAlJwSZgy77wkXKoq73psXI0ZAl_0YC7kgGyz_aE7Wwk3-BLcEqhKyC6yG4RoBzqHJgZXEShYUkCWjdwa5O3ogQ0-dMtjp3jXG-l42RaOJqqNYNegBstQWL874hfYQcVxuWdTeBtqTqXsGp2sH60NEd5h6Z-3Ef0nw0bbCTK0ustCHZn5RN4HHvCiazriqK4CdkPrw"
+
+		verifier, err := jwt.NewVerifier(jwt.RS256, public)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		err = verifier.Verify(tokenStr)
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Verify RS256 with invalid signature", func(t *testing.T) {
+		t.Parallel()
+
+		tokenStr := "eyJhbGciOiJSUzI1NiIsInR5cCI6IkpXVCJ9.e30.ZGV2aWwK"
+
+		verifier, err := jwt.NewVerifier(jwt.RS256, public)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		err = verifier.Verify(tokenStr)
+		if !errors.Is(err, jwt.ErrInvalidTokenSignature) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Verify other algorithm", func(t *testing.T) {
+		t.Parallel()
+
+		tokenStr := "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.e30.HUfJqC1q8JUPKD4jj8PZAYppSrQRL8tJHTljdcTfFCQ"
+
+		verifier, err := jwt.NewVerifier(jwt.HS512, strongSecret)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		err = verifier.Verify(tokenStr)
+		if !errors.Is(err, jwt.ErrUnsupportedAlgorithm) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Verify invalid token", func(t *testing.T) {
+		t.Parallel()
+
+		verifier, err := jwt.NewVerifier(jwt.HS256, strongSecret)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		err = verifier.Verify("invalid")
+		if !errors.Is(err, jwt.ErrInvalidToken) {
+			t.Error(err)
+		}
+	})
+}